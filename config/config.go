@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 )
@@ -16,6 +17,20 @@ type (
 		JWT   `yaml:"jwt"`
 		Redis `yaml:"redis"`
 		Gmail `yaml:"gmail"`
+		MFA   `yaml:"mfa"`
+
+		SessionCache    `yaml:"session_cache"`
+		SessionActivity `yaml:"session_activity"`
+		LoginGuard      `yaml:"login_guard"`
+		OTP             `yaml:"otp"`
+		AccountDeletion `yaml:"account_deletion"`
+		Storage         `yaml:"storage"`
+		Casbin          `yaml:"casbin"`
+		PasswordHash    `yaml:"password_hash"`
+		RateLimit       `yaml:"rate_limit"`
+		Impersonation   `yaml:"impersonation"`
+		Tweet           `yaml:"tweet"`
+		Timeline        `yaml:"timeline"`
 	}
 
 	// App -.
@@ -43,6 +58,11 @@ type (
 	// JWT -.
 	JWT struct {
 		Secret string `env-required:"true" yaml:"secret" env:"JWT_SECRET"`
+		// KeysDir holds RS256/EdDSA keys as <kid>.pem files, HS256 with Secret is used when it is empty
+		KeysDir          string        `yaml:"keys_dir"          env:"JWT_KEYS_DIR"`
+		Algorithm        string        `yaml:"algorithm"         env:"JWT_ALGORITHM"         env-default:"EdDSA"`
+		RotationInterval time.Duration `yaml:"rotation_interval" env:"JWT_ROTATION_INTERVAL" env-default:"0"`
+		KeyRetention     time.Duration `yaml:"key_retention"     env:"JWT_KEY_RETENTION"     env-default:"1h"`
 	}
 
 	// Redis -.
@@ -58,6 +78,124 @@ type (
 		Host      string `env-required:"true" yaml:"host" env:"SMTP_HOST"`
 		Port      string    `env-required:"true" yaml:"port" env:"SMTP_PORT"`
 	}
+
+	// MFA -.
+	MFA struct {
+		Issuer            string   `yaml:"issuer"              env:"MFA_ISSUER"              env-default:"Mini twitter"`
+		EnforcedUserTypes []string `yaml:"enforced_user_types" env:"MFA_ENFORCED_USER_TYPES" env-default:"admin"`
+	}
+
+	// SessionCache -.
+	SessionCache struct {
+		LocalSize int           `yaml:"local_size" env:"SESSION_CACHE_LOCAL_SIZE" env-default:"10000"`
+		LocalTTL  time.Duration `yaml:"local_ttl"  env:"SESSION_CACHE_LOCAL_TTL"  env-default:"5s"`
+		RedisTTL  time.Duration `yaml:"redis_ttl"  env:"SESSION_CACHE_REDIS_TTL"  env-default:"5m"`
+	}
+
+	// SessionActivity -. last_active_at and ip_address of a session are written at most once per UpdateInterval.
+	SessionActivity struct {
+		UpdateInterval time.Duration `yaml:"update_interval" env:"SESSION_ACTIVITY_UPDATE_INTERVAL" env-default:"1m"`
+	}
+
+	// LoginGuard -.
+	LoginGuard struct {
+		MaxAttempts   int           `yaml:"max_attempts"    env:"LOGIN_MAX_ATTEMPTS"    env-default:"10"`
+		IPMaxAttempts int           `yaml:"ip_max_attempts" env:"LOGIN_IP_MAX_ATTEMPTS" env-default:"100"`
+		Window        time.Duration `yaml:"window"          env:"LOGIN_WINDOW"          env-default:"15m"`
+		BackoffAfter  int           `yaml:"backoff_after"   env:"LOGIN_BACKOFF_AFTER"   env-default:"3"`
+		BackoffBase   time.Duration `yaml:"backoff_base"    env:"LOGIN_BACKOFF_BASE"    env-default:"1s"`
+		BackoffMax    time.Duration `yaml:"backoff_max"     env:"LOGIN_BACKOFF_MAX"     env-default:"5m"`
+		LockDuration  time.Duration `yaml:"lock_duration"   env:"LOGIN_LOCK_DURATION"   env-default:"15m"`
+	}
+
+	// OTP -.
+	OTP struct {
+		TTL         time.Duration `yaml:"ttl"          env:"OTP_TTL"          env-default:"5m"`
+		Cooldown    time.Duration `yaml:"cooldown"     env:"OTP_COOLDOWN"     env-default:"1m"`
+		MaxAttempts int           `yaml:"max_attempts" env:"OTP_MAX_ATTEMPTS" env-default:"5"`
+	}
+
+	// AccountDeletion -. Deactivated accounts can be reactivated by logging in during GracePeriod,
+	// after that the purge job deletes them with their tweets and attachment files.
+	AccountDeletion struct {
+		GracePeriod   time.Duration `yaml:"grace_period"   env:"ACCOUNT_DELETION_GRACE_PERIOD"   env-default:"720h"`
+		PurgeInterval time.Duration `yaml:"purge_interval" env:"ACCOUNT_DELETION_PURGE_INTERVAL" env-default:"1h"`
+		BatchSize     int           `yaml:"batch_size"     env:"ACCOUNT_DELETION_BATCH_SIZE"     env-default:"100"`
+	}
+
+	// Casbin -. Policies live in postgres, SeedFile fills an empty casbin_rule table.
+	// Changes are announced on UpdateChannel, ReloadInterval is a fallback for missed notifications.
+	Casbin struct {
+		ModelFile      string        `yaml:"model_file"      env:"CASBIN_MODEL_FILE"      env-default:"config/rbac.conf"`
+		SeedFile       string        `yaml:"seed_file"       env:"CASBIN_SEED_FILE"       env-default:"config/policy.csv"`
+		UpdateChannel  string        `yaml:"update_channel"  env:"CASBIN_UPDATE_CHANNEL"  env-default:"casbin-policy-update"`
+		ReloadInterval time.Duration `yaml:"reload_interval" env:"CASBIN_RELOAD_INTERVAL" env-default:"5m"`
+	}
+
+	// PasswordHash configures new password hashes, bcrypt hashes are still verified
+	// and replaced on the next successful login.
+	PasswordHash struct {
+		Algorithm         string `yaml:"algorithm"          env:"PASSWORD_HASH_ALGORITHM"          env-default:"argon2id"`
+		Argon2Memory      uint32 `yaml:"argon2_memory"      env:"PASSWORD_HASH_ARGON2_MEMORY"      env-default:"65536"`
+		Argon2Iterations  uint32 `yaml:"argon2_iterations"  env:"PASSWORD_HASH_ARGON2_ITERATIONS"  env-default:"3"`
+		Argon2Parallelism uint8  `yaml:"argon2_parallelism" env:"PASSWORD_HASH_ARGON2_PARALLELISM" env-default:"2"`
+		BcryptCost        int    `yaml:"bcrypt_cost"        env:"PASSWORD_HASH_BCRYPT_COST"        env-default:"10"`
+	}
+
+	// Storage -.
+	Storage struct {
+		// AttachmentsDir is the directory attachment file paths are relative to
+		AttachmentsDir string `yaml:"attachments_dir" env:"STORAGE_ATTACHMENTS_DIR" env-default:"./uploads"`
+	}
+
+	// RateLimit rules are checked in order, the first one matching the route applies.
+	RateLimit struct {
+		Enabled bool            `yaml:"enabled" env:"RATE_LIMIT_ENABLED" env-default:"true"`
+		Rules   []RateLimitRule `yaml:"rules"`
+	}
+
+	// RateLimitRule limits requests per user, or per IP for unauthorized requests.
+	RateLimitRule struct {
+		// Path is a route pattern, a trailing * matches any suffix
+		Path string `yaml:"path"`
+		// Method is a list like GET|POST, empty matches any method
+		Method string        `yaml:"method"`
+		Window time.Duration `yaml:"window"`
+		Limit  int           `yaml:"limit"`
+		// Roles overrides Limit per user_role, 0 disables the limit for the role
+		Roles map[string]int `yaml:"roles"`
+	}
+
+	// Impersonation -.
+	Impersonation struct {
+		// TTL is the lifetime of an impersonation session, it can't be refreshed
+		TTL time.Duration `yaml:"ttl" env:"IMPERSONATION_TTL" env-default:"30m"`
+	}
+
+	// Tweet -.
+	Tweet struct {
+		// ConversationMaxSize caps the number of tweets loaded for the conversation view
+		ConversationMaxSize int `yaml:"conversation_max_size" env:"TWEET_CONVERSATION_MAX_SIZE" env-default:"500"`
+		// ScheduleInterval is how often due scheduled tweets are published
+		ScheduleInterval time.Duration `yaml:"schedule_interval" env:"TWEET_SCHEDULE_INTERVAL" env-default:"15s"`
+		// ScheduleBatchSize is the number of tweets published per query
+		ScheduleBatchSize int `yaml:"schedule_batch_size" env:"TWEET_SCHEDULE_BATCH_SIZE" env-default:"100"`
+		// EditWindow is how long a published tweet can be edited, 0 keeps it editable
+		EditWindow time.Duration `yaml:"edit_window" env:"TWEET_EDIT_WINDOW" env-default:"1h"`
+	}
+
+	// Timeline -.
+	Timeline struct {
+		// MaxSize is the number of newest tweets kept in a cached home timeline
+		MaxSize int64 `yaml:"max_size" env:"TIMELINE_MAX_SIZE" env-default:"800"`
+		// TTL drops the cached timelines of inactive users, they are rebuilt on the next read
+		TTL time.Duration `yaml:"ttl" env:"TIMELINE_TTL" env-default:"168h"`
+		// FanOutMaxFollowers is the follower count above which tweets are merged into timelines on read
+		// instead of being pushed to every follower on publish
+		FanOutMaxFollowers int `yaml:"fan_out_max_followers" env:"TIMELINE_FAN_OUT_MAX_FOLLOWERS" env-default:"10000"`
+		// BackfillSize is the number of recent tweets of a newly followed account added to the timeline
+		BackfillSize int `yaml:"backfill_size" env:"TIMELINE_BACKFILL_SIZE" env-default:"50"`
+	}
 )
 
 // NewConfig returns app config.
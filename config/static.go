@@ -17,8 +17,18 @@ var (
 	ErrorConflict       = "CONFLICT"
 	ErrorBadRequest     = "BAD_REQUEST"
 	ErrorDuplicateKey   = "DUPLICATE_KEY"
+	ErrorInvalidMfaCode = "INVALID_MFA_CODE"
+	ErrorMfaRequired    = "MFA_REQUIRED"
+	ErrorAccountLocked  = "ACCOUNT_LOCKED"
+	ErrorTooManyRequest = "TOO_MANY_REQUESTS"
+	ErrorInvalidOtp     = "INVALID_OTP"
+	ErrorOtpExpired     = "OTP_EXPIRED"
+
+	ErrorInsufficientScope = "INSUFFICIENT_SCOPE"
 )
 
 var (
-	TokenExpireTime = 24 * time.Hour * 7 // 7 days
+	TokenExpireTime       = 24 * time.Hour * 7 // 7 days, lifetime of a session and its refresh tokens
+	AccessTokenExpireTime = 15 * time.Minute
+	MfaTokenExpireTime    = 5 * time.Minute
 )
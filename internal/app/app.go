@@ -7,15 +7,21 @@ import (
 	"os/signal"
 	"syscall"
 
+	"github.com/casbin/casbin"
 	"github.com/gin-gonic/gin"
 
 	rediscache "github.com/golanguzb70/redis-cache"
 	"github.com/golanguzb70/udevslabs-twitter/config"
 	v1 "github.com/golanguzb70/udevslabs-twitter/internal/controller/http/v1"
 	"github.com/golanguzb70/udevslabs-twitter/internal/usecase"
+	"github.com/golanguzb70/udevslabs-twitter/internal/worker"
+	"github.com/golanguzb70/udevslabs-twitter/pkg/hash"
 	"github.com/golanguzb70/udevslabs-twitter/pkg/httpserver"
+	"github.com/golanguzb70/udevslabs-twitter/pkg/jwt"
 	"github.com/golanguzb70/udevslabs-twitter/pkg/logger"
+	"github.com/golanguzb70/udevslabs-twitter/pkg/policy"
 	"github.com/golanguzb70/udevslabs-twitter/pkg/postgres"
+	goredis "github.com/redis/go-redis/v9"
 )
 
 // Run creates objects via constructors.
@@ -29,9 +35,6 @@ func Run(cfg *config.Config) {
 	}
 	defer pg.Close()
 
-	// Use case
-	useCase := usecase.New(pg, cfg, l)
-
 	// redis
 	redis, err := rediscache.New(&rediscache.Config{
 		RedisHost: cfg.Redis.RedisHost,
@@ -41,9 +44,68 @@ func Run(cfg *config.Config) {
 		l.Fatal(fmt.Errorf("app - Run - rediscache.New: %w", err))
 	}
 
+	// redis client for atomic operations the cache interface doesn't provide (counters, locks)
+	redisClient := goredis.NewClient(&goredis.Options{
+		Addr: fmt.Sprintf("%s:%d", cfg.Redis.RedisHost, cfg.Redis.RedisPort),
+	})
+	defer redisClient.Close()
+
+	// jwt keys
+	keyRing, err := jwt.NewKeyRing(cfg.JWT.KeysDir,
+		jwt.Secret(cfg.JWT.Secret),
+		jwt.Algorithm(cfg.JWT.Algorithm),
+		jwt.RotationInterval(cfg.JWT.RotationInterval),
+		jwt.Retention(cfg.JWT.KeyRetention),
+	)
+	if err != nil {
+		l.Fatal(fmt.Errorf("app - Run - jwt.NewKeyRing: %w", err))
+	}
+	defer keyRing.Close()
+
+	// password hashing, hashes of the other algorithm are still verified and upgraded on login
+	argon2id := hash.NewArgon2id(cfg.PasswordHash.Argon2Memory, cfg.PasswordHash.Argon2Iterations, cfg.PasswordHash.Argon2Parallelism)
+	bcrypt := hash.NewBcrypt(cfg.PasswordHash.BcryptCost)
+	switch cfg.PasswordHash.Algorithm {
+	case "argon2id":
+		hash.SetPasswordHasher(hash.NewPasswordHasher(argon2id, bcrypt))
+	case "bcrypt":
+		hash.SetPasswordHasher(hash.NewPasswordHasher(bcrypt, argon2id))
+	default:
+		l.Fatal(fmt.Errorf("app - Run - unknown password hash algorithm %q", cfg.PasswordHash.Algorithm))
+	}
+
+	// Access control policies, shared by all instances through postgres and redis
+	enforcer, err := casbin.NewSyncedEnforcerSafe(cfg.Casbin.ModelFile, policy.NewAdapter(pg, l, cfg.Casbin.SeedFile))
+	if err != nil {
+		l.Fatal(fmt.Errorf("app - Run - casbin.NewSyncedEnforcerSafe: %w", err))
+	}
+
+	policyWatcher, err := policy.NewWatcher(redisClient, cfg.Casbin.UpdateChannel)
+	if err != nil {
+		l.Fatal(fmt.Errorf("app - Run - policy.NewWatcher: %w", err))
+	}
+	defer policyWatcher.Close()
+
+	enforcer.SetWatcher(policyWatcher)
+	enforcer.StartAutoLoadPolicy(cfg.Casbin.ReloadInterval)
+
+	// Use case
+	useCase := usecase.New(pg, cfg, l, redis)
+
+	// Background jobs
+	accountPurger := worker.NewAccountPurger(useCase, cfg, l)
+	accountPurger.Start()
+	defer accountPurger.Stop()
+
+	fanOut := worker.NewTimelineFanOut(useCase, redisClient, cfg, l)
+
+	tweetScheduler := worker.NewTweetScheduler(useCase, cfg, l, fanOut)
+	tweetScheduler.Start()
+	defer tweetScheduler.Stop()
+
 	// HTTP Server
 	handler := gin.New()
-	v1.NewRouter(handler, l, cfg, useCase, redis)
+	v1.NewRouter(handler, l, cfg, useCase, redis, redisClient, keyRing, enforcer, fanOut)
 
 	httpServer := httpserver.New(handler, httpserver.Port(cfg.HTTP.Port))
 
@@ -0,0 +1,327 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golanguzb70/udevslabs-twitter/config"
+	"github.com/golanguzb70/udevslabs-twitter/internal/entity"
+	"github.com/golanguzb70/udevslabs-twitter/pkg/etc"
+	"github.com/golanguzb70/udevslabs-twitter/pkg/hash"
+	"github.com/jackc/pgx/v4"
+	"github.com/redis/go-redis/v9"
+)
+
+// ChangePassword godoc
+// @Router /account/change-password [post]
+// @Summary Change password
+// @Description Changes the password of the current user and signs out every other session
+// @Security BearerAuth
+// @Tags account
+// @Accept  json
+// @Produce  json
+// @Param body body entity.ChangePasswordRequest true "Passwords"
+// @Success 200 {object} entity.SuccessResponse
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 423 {object} entity.ErrorResponse
+func (h *Handler) ChangePassword(ctx *gin.Context) {
+	var (
+		body entity.ChangePasswordRequest
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil || body.CurrentPassword == "" || body.NewPassword == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	user, err := h.UseCase.UserRepo.GetSingle(ctx, entity.UserSingleRequest{ID: ctx.GetHeader("sub")})
+	if h.HandleDbError(ctx, err, "Error getting user") {
+		return
+	}
+
+	if h.loginAccountBlocked(ctx, user.ID) {
+		return
+	}
+
+	// wrong guesses count like failed logins, a stolen session can't be used to brute force the password
+	if !hash.CheckPasswordHash(body.CurrentPassword, user.Password) {
+		if h.loginFailed(ctx, user) {
+			h.ReturnError(ctx, config.ErrorAccountLocked, "Too many failed attempts, account is temporarily locked", http.StatusLocked)
+			return
+		}
+
+		h.ReturnError(ctx, config.ErrorInvalidPass, "Current password is incorrect", http.StatusBadRequest)
+		return
+	}
+
+	h.loginSucceeded(ctx, user.ID)
+
+	password, err := hash.HashPassword(body.NewPassword)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorInternalServer, "Oops, something went wrong!!!", http.StatusInternalServerError)
+		return
+	}
+
+	_, err = h.UseCase.UserRepo.UpdateField(ctx, entity.UpdateFieldRequest{
+		Filter: []entity.Filter{{Column: "id", Type: "eq", Value: user.ID}},
+		Items: []entity.UpdateFieldItem{
+			{Column: "password", Value: password},
+			{Column: "updated_at", Value: "now()"},
+		},
+	})
+	if h.HandleDbError(ctx, err, "Error updating password") {
+		return
+	}
+
+	filters := []entity.Filter{
+		{Column: "user_id", Type: "eq", Value: user.ID},
+		{Column: "is_active", Type: "eq", Value: "true"},
+	}
+
+	if sessionID := ctx.GetHeader("session_id"); sessionID != "" {
+		filters = append(filters, entity.Filter{Column: "id", Type: "neq", Value: sessionID})
+	}
+
+	_, err = h.UseCase.SessionRepo.UpdateField(ctx, entity.UpdateFieldRequest{
+		Filter: filters,
+		Items: []entity.UpdateFieldItem{
+			{Column: "is_active", Value: false},
+			{Column: "updated_at", Value: "now()"},
+		},
+	})
+	if h.HandleDbError(ctx, err, "Error deactivating sessions") {
+		return
+	}
+
+	ctx.JSON(200, entity.SuccessResponse{
+		Message: "Password has been changed successfully",
+	})
+}
+
+// ChangeEmail godoc
+// @Router /account/change-email [post]
+// @Summary Change email
+// @Description Sends an otp to the new email address, the email is changed after /account/change-email/verify
+// @Security BearerAuth
+// @Tags account
+// @Accept  json
+// @Produce  json
+// @Param body body entity.ChangeEmailRequest true "New email"
+// @Success 200 {object} entity.SuccessResponse
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 429 {object} entity.ErrorResponse
+func (h *Handler) ChangeEmail(ctx *gin.Context) {
+	var (
+		body entity.ChangeEmailRequest
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	body.NewEmail = strings.TrimSpace(body.NewEmail)
+	if err != nil || body.NewEmail == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	user, err := h.UseCase.UserRepo.GetSingle(ctx, entity.UserSingleRequest{ID: ctx.GetHeader("sub")})
+	if h.HandleDbError(ctx, err, "Error getting user") {
+		return
+	}
+
+	if strings.EqualFold(user.Email, body.NewEmail) {
+		h.ReturnError(ctx, config.ErrorBadRequest, "New email is the same as the current one", http.StatusBadRequest)
+		return
+	}
+
+	_, err = h.UseCase.UserRepo.GetSingle(ctx, entity.UserSingleRequest{Email: body.NewEmail})
+	if err == nil {
+		h.ReturnError(ctx, config.ErrorConflict, "Email is already in use", http.StatusBadRequest)
+		return
+	}
+	if err != pgx.ErrNoRows && h.HandleDbError(ctx, err, "Error getting user") {
+		return
+	}
+
+	err = h.sendOtp(ctx, _otpChangeEmail, body.NewEmail, etc.GenerateEmailChangeEmailBody)
+	if errors.Is(err, errOtpCooldown) {
+		h.setRetryAfter(ctx, otpCooldownKey(_otpChangeEmail, body.NewEmail))
+		h.ReturnError(ctx, config.ErrorTooManyRequest, "Otp was sent recently, please wait before requesting a new one", http.StatusTooManyRequests)
+		return
+	}
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorInternalServer, "Error sending OTP", 500)
+		return
+	}
+
+	// the otp is bound to the new email, the pending change remembers which email it is for this user
+	err = h.RedisClient.Set(ctx, emailChangeKey(user.ID), body.NewEmail, h.Config.OTP.TTL).Err()
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorInternalServer, "Oops, something went wrong!!!", http.StatusInternalServerError)
+		return
+	}
+
+	ctx.JSON(200, entity.SuccessResponse{
+		Message: "Verification code has been sent to the new email address",
+	})
+}
+
+// ChangeEmailVerify godoc
+// @Router /account/change-email/verify [post]
+// @Summary Confirm email change
+// @Description Verifies the otp sent to the new email address, switches the email and notifies the old address
+// @Security BearerAuth
+// @Tags account
+// @Accept  json
+// @Produce  json
+// @Param body body entity.ChangeEmailVerifyRequest true "Otp"
+// @Success 200 {object} entity.SuccessResponse
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) ChangeEmailVerify(ctx *gin.Context) {
+	var (
+		body entity.ChangeEmailVerifyRequest
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil || body.Otp == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	user, err := h.UseCase.UserRepo.GetSingle(ctx, entity.UserSingleRequest{ID: ctx.GetHeader("sub")})
+	if h.HandleDbError(ctx, err, "Error getting user") {
+		return
+	}
+
+	newEmail, err := h.RedisClient.Get(ctx, emailChangeKey(user.ID)).Result()
+	if errors.Is(err, redis.Nil) {
+		h.ReturnError(ctx, config.ErrorOtpExpired, "No pending email change, please request a new one", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorInternalServer, "Oops, something went wrong!!!", http.StatusInternalServerError)
+		return
+	}
+
+	if !h.verifyOtp(ctx, _otpChangeEmail, newEmail, body.Otp) {
+		return
+	}
+
+	// the unique index on email decides if someone took the address in the meantime
+	_, err = h.UseCase.UserRepo.UpdateField(ctx, entity.UpdateFieldRequest{
+		Filter: []entity.Filter{{Column: "id", Type: "eq", Value: user.ID}},
+		Items: []entity.UpdateFieldItem{
+			{Column: "email", Value: newEmail},
+			{Column: "updated_at", Value: "now()"},
+		},
+	})
+	if h.HandleDbError(ctx, err, "Error updating email") {
+		return
+	}
+
+	h.RedisClient.Del(ctx, emailChangeKey(user.ID))
+
+	go h.sendEmailChangedEmail(user.Email, newEmail)
+
+	ctx.JSON(200, entity.SuccessResponse{
+		Message: "Email has been changed successfully",
+	})
+}
+
+func (h *Handler) sendEmailChangedEmail(oldEmail, newEmail string) {
+	emailBody, err := etc.GenerateEmailChangedEmailBody(newEmail)
+	if err != nil {
+		h.Logger.Error(err, "Error generating email changed email")
+		return
+	}
+
+	err = etc.SendEmailWithSubject(h.Config.Gmail.Host, h.Config.Gmail.Port, h.Config.Gmail.Email, h.Config.Gmail.EmailPass,
+		oldEmail, "Mini twitter email changed", emailBody)
+	if err != nil {
+		h.Logger.Error(err, "Error sending email changed email")
+	}
+}
+
+func emailChangeKey(userID string) string {
+	return fmt.Sprintf("email-change-%s", userID)
+}
+
+// DeactivateAccount godoc
+// @Router /account/deactivate [post]
+// @Summary Deactivate account
+// @Description Hides the profile and tweets and signs out every session. The account is deleted permanently
+// @Description after the grace period, logging in before that reactivates it.
+// @Security BearerAuth
+// @Tags account
+// @Accept  json
+// @Produce  json
+// @Param body body entity.DeactivateAccountRequest true "Password"
+// @Success 200 {object} entity.User
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) DeactivateAccount(ctx *gin.Context) {
+	var (
+		body entity.DeactivateAccountRequest
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil || body.Password == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	user, err := h.UseCase.UserRepo.GetSingle(ctx, entity.UserSingleRequest{ID: ctx.GetHeader("sub")})
+	if h.HandleDbError(ctx, err, "Error getting user") {
+		return
+	}
+
+	if !hash.CheckPasswordHash(body.Password, user.Password) {
+		h.ReturnError(ctx, config.ErrorInvalidPass, "Incorrect password", http.StatusBadRequest)
+		return
+	}
+
+	h.deactivateAccount(ctx, user)
+}
+
+// deactivateAccount schedules the deletion of the account after AccountDeletion.GracePeriod
+// and signs out every session, personal access tokens of deactivated accounts are rejected.
+func (h *Handler) deactivateAccount(ctx *gin.Context, user entity.User) {
+	now := time.Now().UTC()
+	deleteAfter := now.Add(h.Config.AccountDeletion.GracePeriod)
+
+	_, err := h.UseCase.UserRepo.UpdateField(ctx, entity.UpdateFieldRequest{
+		Filter: []entity.Filter{{Column: "id", Type: "eq", Value: user.ID}},
+		Items: []entity.UpdateFieldItem{
+			{Column: "status", Value: "deactivated"},
+			{Column: "deactivated_at", Value: now},
+			{Column: "delete_after", Value: deleteAfter},
+			{Column: "updated_at", Value: "now()"},
+		},
+	})
+	if h.HandleDbError(ctx, err, "Error deactivating account") {
+		return
+	}
+
+	_, err = h.UseCase.SessionRepo.UpdateField(ctx, entity.UpdateFieldRequest{
+		Filter: []entity.Filter{
+			{Column: "user_id", Type: "eq", Value: user.ID},
+			{Column: "is_active", Type: "eq", Value: "true"},
+		},
+		Items: []entity.UpdateFieldItem{
+			{Column: "is_active", Value: false},
+			{Column: "updated_at", Value: "now()"},
+		},
+	})
+	if h.HandleDbError(ctx, err, "Error deactivating sessions") {
+		return
+	}
+
+	user.Password = ""
+	user.Status = "deactivated"
+	user.DeactivatedAt = now.Format(time.RFC3339)
+	user.DeleteAfter = deleteAfter.Format(time.RFC3339)
+
+	ctx.JSON(200, user)
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
@@ -10,19 +11,23 @@ import (
 	"github.com/golanguzb70/udevslabs-twitter/internal/entity"
 	"github.com/golanguzb70/udevslabs-twitter/pkg/etc"
 	"github.com/golanguzb70/udevslabs-twitter/pkg/hash"
-	"github.com/golanguzb70/udevslabs-twitter/pkg/jwt"
+	"github.com/jackc/pgx/v4"
 )
 
 // Login godoc
 // @Router /auth/login [post]
 // @Summary Login
-// @Description Login
+// @Description Login. If two-factor authentication is enabled or required for the user,
+// @Description an mfa token is returned instead of a session, see /auth/mfa/verify.
 // @Tags auth
 // @Accept  json
 // @Produce  json
 // @Param body body entity.LoginRequest true "User"
 // @Success 200 {object} entity.SuccessResponse
+// @Success 202 {object} entity.MfaChallengeResponse
 // @Failure 400 {object} entity.ErrorResponse
+// @Failure 423 {object} entity.ErrorResponse
+// @Failure 429 {object} entity.ErrorResponse
 func (h *Handler) Login(ctx *gin.Context) {
 	var (
 		body entity.LoginRequest
@@ -34,14 +39,25 @@ func (h *Handler) Login(ctx *gin.Context) {
 		return
 	}
 
+	if h.loginIPBlocked(ctx) {
+		return
+	}
+
 	user, err := h.UseCase.UserRepo.GetSingle(ctx, entity.UserSingleRequest{
 		UserName: body.Username,
 		Email:    body.Email,
 	})
+	if err == pgx.ErrNoRows {
+		h.loginFailed(ctx, entity.User{})
+	}
 	if h.HandleDbError(ctx, err, "Error getting user") {
 		return
 	}
 
+	if h.loginAccountBlocked(ctx, user.ID) {
+		return
+	}
+
 	if user.UserType == "user" && body.Platform == "admin" {
 		h.ReturnError(ctx, config.ErrorForbidden, "User can't login to admin web", http.StatusBadRequest)
 		return
@@ -51,38 +67,31 @@ func (h *Handler) Login(ctx *gin.Context) {
 	}
 
 	if !hash.CheckPasswordHash(body.Password, user.Password) {
+		if h.loginFailed(ctx, user) {
+			h.ReturnError(ctx, config.ErrorAccountLocked, "Too many failed login attempts, account is temporarily locked", http.StatusLocked)
+			return
+		}
+
 		h.ReturnError(ctx, config.ErrorInvalidPass, "Incorrect password", http.StatusBadRequest)
 		return
 	}
 
-	// create session
-	newSession := entity.Session{
-		UserID:       user.ID,
-		IPAddress:    ctx.ClientIP(),
-		ExpiresAt:    time.Now().Add(time.Hour * 999999).Format(time.RFC3339),
-		UserAgent:    ctx.Request.UserAgent(),
-		IsActive:     true,
-		LastActiveAt: time.Now().Format(time.RFC3339),
-		Platform:     body.Platform,
-	}
+	h.loginSucceeded(ctx, user.ID)
+	h.rehashPassword(ctx, user, body.Password)
 
-	session, err := h.UseCase.SessionRepo.Create(ctx, newSession)
-	if h.HandleDbError(ctx, err, "Error while creating new session") {
+	// the purge job will delete the account soon, it can't be reactivated anymore
+	if user.Status == "deactivated" && isExpired(user.DeleteAfter) {
+		h.ReturnError(ctx, config.ErrorNotFound, "The requested resource was not found.", http.StatusNotFound)
 		return
 	}
 
-	// generate jwt token
-	jwtFields := map[string]interface{}{
-		"sub":        user.ID,
-		"user_role":  user.UserRole,
-		"user_type":  user.UserType,
-		"platform":   body.Platform,
-		"session_id": session.ID,
+	if user.TotpEnabled || h.isMfaEnforced(user) {
+		h.mfaChallenge(ctx, user, body.Platform)
+		return
 	}
 
-	user.AccessToken, err = jwt.GenerateJWT(jwtFields, h.Config.JWT.Secret)
-	if err != nil {
-		h.ReturnError(ctx, config.ErrorInternalServer, "Oops, something went wrong!!!", http.StatusInternalServerError)
+	user, session, err := h.createSession(ctx, user, body.Platform)
+	if h.HandleDbError(ctx, err, "Error while creating new session") {
 		return
 	}
 
@@ -146,6 +155,10 @@ func (h *Handler) Register(ctx *gin.Context) {
 		UserName: body.Username,
 		Email:    body.Email,
 	})
+	if err == nil && user.Status == "inverify" {
+		h.ReturnError(ctx, config.ErrorConflict, "User already exists, verify the email or request a new code via /auth/resend-otp", 400)
+		return
+	}
 	if err == nil {
 		h.ReturnError(ctx, config.ErrorConflict, "User already exists", 400)
 		return
@@ -171,23 +184,9 @@ func (h *Handler) Register(ctx *gin.Context) {
 		return
 	}
 
-	// send verification code to user
-	otp := etc.GenerateOTP(6)
-	err = h.Redis.Set(ctx, fmt.Sprintf("otp-%s", user.Email), otp, 5*60)
-	if err != nil {
-		h.ReturnError(ctx, config.ErrorInternalServer, "Error setting OTP", 500)
-		return
-	}
-
-	// send otp code to user's email
-	emailBody, err := etc.GenerateOtpEmailBody(otp)
-	if err != nil {
-		h.ReturnError(ctx, config.ErrorInternalServer, "Error sending OTP", 500)
-		return
-	}
-
-	err = etc.SendEmail(h.Config.Gmail.Host, h.Config.Gmail.Port, h.Config.Gmail.Email, h.Config.Gmail.EmailPass, body.Email, emailBody)
-	if err != nil {
+	// send verification code to user's email
+	err = h.sendOtp(ctx, _otpVerifyEmail, user.Email, etc.GenerateOtpEmailBody)
+	if err != nil && !errors.Is(err, errOtpCooldown) {
 		h.ReturnError(ctx, config.ErrorInternalServer, "Error sending OTP", 500)
 		return
 	}
@@ -218,66 +217,434 @@ func (h *Handler) VerifyEmail(ctx *gin.Context) {
 		return
 	}
 
-	key := fmt.Sprintf("otp-%s", body.Email)
+	user, err := h.UseCase.UserRepo.GetSingle(ctx, entity.UserSingleRequest{
+		Email: body.Email,
+	})
+	if h.HandleDbError(ctx, err, "get single user") {
+		return
+	}
+
+	// only signs up here, verified accounts log in with their password and second factor
+	if user.Status != "inverify" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Email is already verified", http.StatusBadRequest)
+		return
+	}
+
+	if !h.verifyOtp(ctx, _otpVerifyEmail, body.Email, body.Otp) {
+		return
+	}
+
+	user.Status = "active"
+
+	_, err = h.UseCase.UserRepo.Update(ctx, user)
+	if h.HandleDbError(ctx, err, "update user") {
+		return
+	}
+
+	user, session, err := h.createSession(ctx, user, body.Platform)
+	if h.HandleDbError(ctx, err, "Error while creating new session") {
+		return
+	}
+
+	ctx.JSON(200, gin.H{
+		"user":    user,
+		"session": session,
+	})
+}
+
+// RefreshToken godoc
+// @Router /auth/refresh [post]
+// @Summary Refresh access token
+// @Description Exchanges a refresh token for a new access token and a new refresh token.
+// @Description Every refresh token can be used only once, replaying a used one revokes the whole session.
+// @Tags auth
+// @Accept  json
+// @Produce  json
+// @Param body body entity.RefreshTokenRequest true "Refresh token"
+// @Success 200 {object} entity.TokenResponse
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+func (h *Handler) RefreshToken(ctx *gin.Context) {
+	var (
+		body entity.RefreshTokenRequest
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil || body.RefreshToken == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	refreshToken, err := h.UseCase.RefreshTokenRepo.GetSingle(ctx, entity.RefreshTokenSingleRequest{
+		TokenHash: hash.HashToken(body.RefreshToken),
+	})
+	if err == pgx.ErrNoRows {
+		h.ReturnError(ctx, config.ErrorInvalidToken, "Invalid refresh token", http.StatusUnauthorized)
+		return
+	}
+	if h.HandleDbError(ctx, err, "Error getting refresh token") {
+		return
+	}
 
-	otp, err := h.Redis.Get(ctx, key)
+	if refreshToken.IsUsed {
+		h.revokeSession(ctx, refreshToken.SessionID)
+		return
+	}
+
+	session, err := h.UseCase.SessionRepo.GetSingle(ctx, entity.Id{ID: refreshToken.SessionID})
+	if h.HandleDbError(ctx, err, "Error getting session") {
+		return
+	}
+
+	if !session.IsActive {
+		h.ReturnError(ctx, config.ErrorInvalidToken, "Session is not active", http.StatusUnauthorized)
+		return
+	}
+
+	if isExpired(session.ExpiresAt) {
+		h.ReturnError(ctx, config.ErrorSessionExpired, "Session is expired", http.StatusUnauthorized)
+		return
+	}
+
+	// mark the token as used, the is_used filter makes concurrent refreshes with the same token lose
+	rows, err := h.UseCase.RefreshTokenRepo.UpdateField(ctx, entity.UpdateFieldRequest{
+		Filter: []entity.Filter{
+			{Column: "id", Type: "eq", Value: refreshToken.ID},
+			{Column: "is_used", Type: "eq", Value: "false"},
+		},
+		Items: []entity.UpdateFieldItem{
+			{Column: "is_used", Value: true},
+			{Column: "updated_at", Value: "now()"},
+		},
+	})
+	if h.HandleDbError(ctx, err, "Error updating refresh token") {
+		return
+	}
+
+	if rows.RowsEffected == 0 {
+		h.revokeSession(ctx, refreshToken.SessionID)
+		return
+	}
+
+	user, err := h.UseCase.UserRepo.GetSingle(ctx, entity.UserSingleRequest{ID: session.UserID})
+	if h.HandleDbError(ctx, err, "Error getting user") {
+		return
+	}
+
+	response := entity.TokenResponse{}
+
+	response.RefreshToken, err = h.createRefreshToken(ctx, session.ID)
+	if h.HandleDbError(ctx, err, "Error creating refresh token") {
+		return
+	}
+
+	response.AccessToken, response.ExpiresAt, err = h.generateAccessToken(user, session)
 	if err != nil {
-		h.ReturnError(ctx, config.ErrorInternalServer, "Ooops, something went wrong", http.StatusInternalServerError)
+		h.ReturnError(ctx, config.ErrorInternalServer, "Oops, something went wrong!!!", http.StatusInternalServerError)
+		return
+	}
+
+	ctx.JSON(200, response)
+}
+
+// ForgotPassword godoc
+// @Router /auth/forgot-password [post]
+// @Summary Forgot password
+// @Description Sends a password reset otp to the user's email address
+// @Tags auth
+// @Accept  json
+// @Produce  json
+// @Param body body entity.ForgotPasswordRequest true "Email"
+// @Success 200 {object} entity.SuccessResponse
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) ForgotPassword(ctx *gin.Context) {
+	var (
+		body entity.ForgotPasswordRequest
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil || body.Email == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	// the same response is returned for unknown emails, so the endpoint can't be used to enumerate users
+	response := entity.SuccessResponse{
+		Message: "If the email is registered, a password reset code has been sent to it",
+	}
+
+	user, err := h.UseCase.UserRepo.GetSingle(ctx, entity.UserSingleRequest{
+		Email: body.Email,
+	})
+	if err == pgx.ErrNoRows {
+		ctx.JSON(200, response)
+		return
+	}
+	if h.HandleDbError(ctx, err, "Error getting user") {
+		return
+	}
+
+	if user.Status == "blocked" {
+		ctx.JSON(200, response)
 		return
 	}
 
-	if otp != body.Otp {
-		h.ReturnError(ctx, config.ErrorBadRequest, "Incorrect otp", http.StatusBadRequest)
+	// during the cooldown the previous code is still valid, nothing is sent
+	err = h.sendOtp(ctx, _otpResetPassword, user.Email, etc.GeneratePasswordResetEmailBody)
+	if err != nil && !errors.Is(err, errOtpCooldown) {
+		h.ReturnError(ctx, config.ErrorInternalServer, "Error sending OTP", 500)
+		return
+	}
+
+	ctx.JSON(200, response)
+}
+
+// ResetPassword godoc
+// @Router /auth/reset-password [post]
+// @Summary Reset password
+// @Description Sets a new password using the otp sent by forgot-password and signs the user out of every session
+// @Tags auth
+// @Accept  json
+// @Produce  json
+// @Param body body entity.ResetPasswordRequest true "Reset password"
+// @Success 200 {object} entity.SuccessResponse
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) ResetPassword(ctx *gin.Context) {
+	var (
+		body entity.ResetPasswordRequest
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil || body.Email == "" || body.NewPassword == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	if !h.verifyOtp(ctx, _otpResetPassword, body.Email, body.Otp) {
 		return
 	}
 
 	user, err := h.UseCase.UserRepo.GetSingle(ctx, entity.UserSingleRequest{
 		Email: body.Email,
 	})
-	if h.HandleDbError(ctx, err, "get single user") {
+	if h.HandleDbError(ctx, err, "Error getting user") {
 		return
 	}
 
-	user.Status = "active"
+	password, err := hash.HashPassword(body.NewPassword)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorInternalServer, "Oops, something went wrong!!!", http.StatusInternalServerError)
+		return
+	}
 
-	_, err = h.UseCase.UserRepo.Update(ctx, user)
-	if h.HandleDbError(ctx, err, "update user") {
+	_, err = h.UseCase.UserRepo.UpdateField(ctx, entity.UpdateFieldRequest{
+		Filter: []entity.Filter{{Column: "id", Type: "eq", Value: user.ID}},
+		Items: []entity.UpdateFieldItem{
+			{Column: "password", Value: password},
+			{Column: "updated_at", Value: "now()"},
+		},
+	})
+	if h.HandleDbError(ctx, err, "Error updating password") {
+		return
+	}
+
+	// sign out from every device, tokens issued before the reset must stop working
+	_, err = h.UseCase.SessionRepo.UpdateField(ctx, entity.UpdateFieldRequest{
+		Filter: []entity.Filter{{Column: "user_id", Type: "eq", Value: user.ID}},
+		Items: []entity.UpdateFieldItem{
+			{Column: "is_active", Value: false},
+			{Column: "updated_at", Value: "now()"},
+		},
+	})
+	if h.HandleDbError(ctx, err, "Error deactivating sessions") {
+		return
+	}
+
+	ctx.JSON(200, entity.SuccessResponse{
+		Message: "Password has been reset successfully",
+	})
+}
+
+// revokeSession is called when an already rotated refresh token is presented again.
+// The token may have been stolen, so the session and every refresh token issued for it are revoked.
+func (h *Handler) revokeSession(ctx *gin.Context, sessionID string) {
+	h.Logger.Warn(fmt.Sprintf("refresh token reuse detected, revoking session %s", sessionID))
+
+	_, err := h.UseCase.SessionRepo.UpdateField(ctx, entity.UpdateFieldRequest{
+		Filter: []entity.Filter{{Column: "id", Type: "eq", Value: sessionID}},
+		Items: []entity.UpdateFieldItem{
+			{Column: "is_active", Value: false},
+			{Column: "updated_at", Value: "now()"},
+		},
+	})
+	if h.HandleDbError(ctx, err, "Error revoking session") {
+		return
+	}
+
+	_, err = h.UseCase.RefreshTokenRepo.UpdateField(ctx, entity.UpdateFieldRequest{
+		Filter: []entity.Filter{{Column: "session_id", Type: "eq", Value: sessionID}},
+		Items: []entity.UpdateFieldItem{
+			{Column: "is_used", Value: true},
+			{Column: "updated_at", Value: "now()"},
+		},
+	})
+	if h.HandleDbError(ctx, err, "Error revoking refresh tokens") {
 		return
 	}
 
-	// create session
-	newSession := entity.Session{
+	h.ReturnError(ctx, config.ErrorInvalidToken, "Refresh token was already used, session is revoked", http.StatusUnauthorized)
+}
+
+// createSession creates a new session for the user and fills user's access and refresh tokens.
+// A deactivated account is reactivated, signing in during the grace period cancels the deletion.
+func (h *Handler) createSession(ctx *gin.Context, user entity.User, platform string) (entity.User, entity.Session, error) {
+	now := time.Now().UTC()
+
+	if user.Status == "deactivated" {
+		_, err := h.UseCase.UserRepo.UpdateField(ctx, entity.UpdateFieldRequest{
+			Filter: []entity.Filter{{Column: "id", Type: "eq", Value: user.ID}},
+			Items: []entity.UpdateFieldItem{
+				{Column: "status", Value: "active"},
+				{Column: "deactivated_at", Value: nil},
+				{Column: "delete_after", Value: nil},
+				{Column: "updated_at", Value: "now()"},
+			},
+		})
+		if err != nil {
+			return user, entity.Session{}, err
+		}
+
+		user.Status, user.DeactivatedAt, user.DeleteAfter = "active", "", ""
+	}
+
+	session, err := h.UseCase.SessionRepo.Create(ctx, entity.Session{
 		UserID:       user.ID,
 		IPAddress:    ctx.ClientIP(),
-		ExpiresAt:    time.Now().Add(time.Hour * 999999).Format(time.RFC3339),
+		ExpiresAt:    now.Add(config.TokenExpireTime).Format(time.RFC3339),
 		UserAgent:    ctx.Request.UserAgent(),
 		IsActive:     true,
-		LastActiveAt: time.Now().Format(time.RFC3339),
-		Platform:     body.Platform,
+		LastActiveAt: now.Format(time.RFC3339),
+		Platform:     platform,
+	})
+	if err != nil {
+		return user, entity.Session{}, err
 	}
 
-	session, err := h.UseCase.SessionRepo.Create(ctx, newSession)
-	if h.HandleDbError(ctx, err, "Error while creating new session") {
-		return
+	user.RefreshToken, err = h.createRefreshToken(ctx, session.ID)
+	if err != nil {
+		return user, entity.Session{}, err
+	}
+
+	user.AccessToken, _, err = h.generateAccessToken(user, session)
+	if err != nil {
+		return user, entity.Session{}, err
 	}
 
-	// generate jwt token
+	return user, session, nil
+}
+
+// createRefreshToken generates an opaque refresh token for the session, only its hash is stored.
+func (h *Handler) createRefreshToken(ctx *gin.Context, sessionID string) (string, error) {
+	token, err := etc.GenerateToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = h.UseCase.RefreshTokenRepo.Create(ctx, entity.RefreshToken{
+		SessionID: sessionID,
+		TokenHash: hash.HashToken(token),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// generateAccessToken returns a short-lived jwt token and its expiration time,
+// tokens of impersonation sessions expire with the session.
+func (h *Handler) generateAccessToken(user entity.User, session entity.Session) (string, string, error) {
+	now := time.Now().UTC()
+	expiresAt := now.Add(config.AccessTokenExpireTime)
+
 	jwtFields := map[string]interface{}{
 		"sub":        user.ID,
 		"user_role":  user.UserRole,
 		"user_type":  user.UserType,
-		"platform":   body.Platform,
+		"platform":   session.Platform,
 		"session_id": session.ID,
+		"iat":        now.Unix(),
+	}
+
+	if session.ImpersonatorID != "" {
+		jwtFields["act"] = map[string]interface{}{"sub": session.ImpersonatorID}
+
+		// impersonation sessions can't be refreshed, the token lasts as long as the session
+		sessionExpiresAt, err := time.Parse(time.RFC3339, session.ExpiresAt)
+		if err == nil {
+			expiresAt = sessionExpiresAt
+		}
 	}
+	jwtFields["exp"] = expiresAt.Unix()
 
-	user.AccessToken, err = jwt.GenerateJWT(jwtFields, h.Config.JWT.Secret)
+	token, err := h.KeyRing.Generate(jwtFields)
 	if err != nil {
-		h.ReturnError(ctx, config.ErrorInternalServer, "Oops, something went wrong!!!", http.StatusInternalServerError)
+		return "", "", err
+	}
+
+	return token, expiresAt.Format(time.RFC3339), nil
+}
+
+// isExpired reports whether the RFC3339 formatted time is in the past.
+func isExpired(expiresAt string) bool {
+	if expiresAt == "" {
+		return false
+	}
+
+	t, err := time.Parse(time.RFC3339, expiresAt)
+	if err != nil {
+		return false
+	}
+
+	return time.Now().UTC().After(t)
+}
+
+// JWKS godoc
+// @Router /.well-known/jwks.json [get]
+// @Summary JSON Web Key Set
+// @Description Public keys to verify access tokens, the kid header of a token names its key. Empty when tokens are signed with HS256.
+// @Tags auth
+// @Produce  json
+// @Success 200 {object} jwt.JWKS
+func (h *Handler) JWKS(ctx *gin.Context) {
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(200, h.KeyRing.JWKS())
+}
+
+// rehashPassword replaces a hash made with another algorithm or outdated parameters,
+// it is possible only here while the plaintext password is at hand.
+func (h *Handler) rehashPassword(ctx *gin.Context, user entity.User, password string) {
+	if !hash.NeedsRehash(user.Password) {
 		return
 	}
 
-	ctx.JSON(200, gin.H{
-		"user":    user,
-		"session": session,
+	newHash, err := hash.HashPassword(password)
+	if err != nil {
+		h.Logger.Error(err, "Error rehashing password")
+		return
+	}
+
+	// the password filter keeps a concurrent password change from being overwritten
+	_, err = h.UseCase.UserRepo.UpdateField(ctx, entity.UpdateFieldRequest{
+		Filter: []entity.Filter{
+			{Column: "id", Type: "eq", Value: user.ID},
+			{Column: "password", Type: "eq", Value: user.Password},
+		},
+		Items: []entity.UpdateFieldItem{
+			{Column: "password", Value: newHash},
+		},
 	})
+	if err != nil {
+		h.Logger.Error(err, "Error saving rehashed password")
+	}
 }
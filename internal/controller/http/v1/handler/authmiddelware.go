@@ -1,33 +1,59 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
 
 	"github.com/casbin/casbin"
 	"github.com/gin-gonic/gin"
+	"github.com/golanguzb70/udevslabs-twitter/config"
 	"github.com/golanguzb70/udevslabs-twitter/internal/entity"
 	"github.com/golanguzb70/udevslabs-twitter/pkg/jwt"
 )
 
-func (h *Handler) AuthMiddleware(e *casbin.Enforcer) gin.HandlerFunc {
+func (h *Handler) AuthMiddleware(e *casbin.SyncedEnforcer) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var (
-			userRole string
-			act      = c.Request.Method
-			obj      = c.FullPath()
+			userRole      string
+			personalToken bool
+			act           = c.Request.Method
+			obj           = c.FullPath()
 		)
 
+		// set only by authenticateToken and for impersonation sessions
+		c.Request.Header.Del("token_id")
+		c.Request.Header.Del("act_sub")
+
 		token := c.GetHeader("Authorization")
 		if token == "" {
 			userRole = "unauthorized"
 		}
 
+		if userRole == "" && isPersonalAccessToken(strings.TrimPrefix(token, "Bearer ")) {
+			role, ok := h.authenticateToken(c, strings.TrimPrefix(token, "Bearer "))
+			if !ok {
+				return
+			}
+
+			userRole, personalToken = role, true
+		}
+
 		if userRole == "" {
 			token = strings.TrimPrefix(token, "Bearer ")
 
-			claims, err := jwt.ParseJWT(token, h.Config.JWT.Secret)
+			claims, err := h.KeyRing.Parse(token)
+			if errors.Is(err, jwt.ErrTokenExpired) {
+				// expired tokens are still fine for public routes, e.g. /v1/auth/refresh
+				if ok, _ := enforce(e, "unauthorized", obj, act); !ok {
+					c.AbortWithStatusJSON(http.StatusUnauthorized, entity.ErrorResponse{
+						Message: "Access token is expired",
+						Code:    config.ErrorSessionExpired,
+					})
+					return
+				}
+			}
 			if err != nil {
 				userRole = "unauthorized"
 			}
@@ -40,16 +66,20 @@ func (h *Handler) AuthMiddleware(e *casbin.Enforcer) gin.HandlerFunc {
 			}
 
 			for key, value := range claims {
+				// the actor is taken from the session, see below
+				if key == "act" {
+					continue
+				}
 				c.Request.Header.Set(key, fmt.Sprintf("%v", value))
 			}
 		}
 
 		// TO DO: Check if session is valid
 
-		if userRole != "unauthorized" {
+		if userRole != "unauthorized" && !personalToken {
 			session, err := h.UseCase.SessionRepo.GetSingle(c, entity.Id{ID: c.GetHeader("session_id")})
 			if err != nil {
-				fmt.Println("error while gettign single session", err)
+				h.Logger.Error(err, "Error getting session")
 				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Session is invalid"})
 				return
 			}
@@ -58,9 +88,31 @@ func (h *Handler) AuthMiddleware(e *casbin.Enforcer) gin.HandlerFunc {
 				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Session is not active"})
 				return
 			}
+
+			if isExpired(session.ExpiresAt) {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, entity.ErrorResponse{
+					Message: "Session is expired",
+					Code:    config.ErrorSessionExpired,
+				})
+				return
+			}
+
+			h.touchSession(c, session)
+
+			if session.ImpersonatorID != "" {
+				c.Request.Header.Set("act_sub", session.ImpersonatorID)
+				c.Header("X-Impersonated-By", session.ImpersonatorID)
+				defer h.auditImpersonation(c, session)
+			}
+		}
+
+		// later middlewares and handlers rely on these, so they can't come from the client
+		c.Request.Header.Set("user_role", userRole)
+		if userRole == "unauthorized" {
+			c.Request.Header.Del("sub")
 		}
 
-		ok, err := e.EnforceSafe(userRole, obj, act)
+		ok, err := enforce(e, userRole, obj, act)
 		if err != nil {
 			h.Logger.Error(err, "Error enforcing policy")
 			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "access denied"})
@@ -75,3 +127,15 @@ func (h *Handler) AuthMiddleware(e *casbin.Enforcer) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// enforce checks the request against the policies under the read lock of the enforcer, policies are reloaded
+// concurrently by the watcher. Enforce panics on errors in the model, they are returned instead.
+func enforce(e *casbin.SyncedEnforcer, sub, obj, act string) (ok bool, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			ok, err = false, fmt.Errorf("%v", r)
+		}
+	}()
+
+	return e.Enforce(sub, obj, act), nil
+}
@@ -0,0 +1,337 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golanguzb70/udevslabs-twitter/config"
+	"github.com/golanguzb70/udevslabs-twitter/internal/entity"
+)
+
+// ToggleBookmark godoc
+// @Router /bookmark [post]
+// @Summary Bookmark a tweet or remove the bookmark
+// @Description Bookmarks are private, the author isn't notified. folder_id is optional.
+// @Security BearerAuth
+// @Tags bookmark
+// @Accept  json
+// @Produce  json
+// @Param bookmark body entity.Bookmark true "Bookmark"
+// @Success 200 {object} entity.Bookmark
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) ToggleBookmark(ctx *gin.Context) {
+	var (
+		body entity.Bookmark
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	body.UserId = ctx.GetHeader("sub")
+
+	tweet, err := h.UseCase.TweetRepo.GetSingle(ctx, entity.Id{ID: body.TweetId})
+	if h.HandleDbError(ctx, err, "Error getting tweet") {
+		return
+	}
+
+	if tweet.Status != "published" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Only published tweets can be bookmarked", http.StatusBadRequest)
+		return
+	}
+
+	if !h.checkBookmarkFolder(ctx, body.FolderId) {
+		return
+	}
+
+	bookmark, err := h.UseCase.BookmarkRepo.UpsertOrRemove(ctx, body)
+	if h.HandleDbError(ctx, err, "Error bookmarking tweet") {
+		return
+	}
+
+	ctx.JSON(200, bookmark)
+}
+
+// MoveBookmark godoc
+// @Router /bookmark [put]
+// @Summary Move a bookmark to another folder
+// @Description An empty folder_id moves the bookmark out of its folder
+// @Security BearerAuth
+// @Tags bookmark
+// @Accept  json
+// @Produce  json
+// @Param bookmark body entity.Bookmark true "Bookmark"
+// @Success 200 {object} entity.Bookmark
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) MoveBookmark(ctx *gin.Context) {
+	var (
+		body entity.Bookmark
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	if !h.checkBookmarkFolder(ctx, body.FolderId) {
+		return
+	}
+
+	var folderID interface{}
+	if body.FolderId != "" {
+		folderID = body.FolderId
+	}
+
+	rows, err := h.UseCase.BookmarkRepo.UpdateField(ctx, entity.UpdateFieldRequest{
+		Filter: []entity.Filter{
+			{Column: "user_id", Type: "eq", Value: ctx.GetHeader("sub")},
+			{Column: "tweet_id", Type: "eq", Value: body.TweetId},
+		},
+		Items: []entity.UpdateFieldItem{
+			{Column: "folder_id", Value: folderID},
+			{Column: "updated_at", Value: "now()"},
+		},
+	})
+	if h.HandleDbError(ctx, err, "Error moving bookmark") {
+		return
+	}
+
+	if rows.RowsEffected == 0 {
+		h.ReturnError(ctx, config.ErrorNotFound, "The tweet is not bookmarked", http.StatusNotFound)
+		return
+	}
+
+	body.Bookmarked = true
+
+	ctx.JSON(200, body)
+}
+
+// GetBookmarks godoc
+// @Router /bookmark/list [get]
+// @Summary Get the bookmarked tweets
+// @Description Latest bookmarks first, folder_id narrows the list to a folder
+// @Security BearerAuth
+// @Tags bookmark
+// @Accept  json
+// @Produce  json
+// @Param page query number true "page"
+// @Param limit query number true "limit"
+// @Param folder_id query string false "folder_id"
+// @Success 200 {object} entity.TweetList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetBookmarks(ctx *gin.Context) {
+	var (
+		req entity.GetListFilter
+	)
+
+	page := ctx.DefaultQuery("page", "1")
+	limit := ctx.DefaultQuery("limit", "10")
+	folderID := ctx.DefaultQuery("folder_id", "")
+
+	req.Page, _ = strconv.Atoi(page)
+	req.Limit, _ = strconv.Atoi(limit)
+	req.Filters = append(req.Filters, entity.Filter{
+		Column: "b.user_id",
+		Type:   "eq",
+		Value:  ctx.GetHeader("sub"),
+	})
+
+	if folderID != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "b.folder_id",
+			Type:   "eq",
+			Value:  folderID,
+		})
+	}
+
+	req.OrderBy = append(req.OrderBy, entity.OrderBy{
+		Column: "b.created_at",
+		Order:  "desc",
+	})
+
+	tweets, err := h.UseCase.TweetRepo.GetBookmarks(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting bookmarks") {
+		return
+	}
+
+	if !h.setViewerState(ctx, tweets.Items) {
+		return
+	}
+
+	ctx.JSON(200, tweets)
+}
+
+// CreateBookmarkFolder godoc
+// @Router /bookmark/folder [post]
+// @Summary Create a bookmark folder
+// @Description Create a bookmark folder
+// @Security BearerAuth
+// @Tags bookmark
+// @Accept  json
+// @Produce  json
+// @Param folder body entity.BookmarkFolder true "Folder"
+// @Success 200 {object} entity.BookmarkFolder
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) CreateBookmarkFolder(ctx *gin.Context) {
+	var (
+		body entity.BookmarkFolder
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	body.Name = strings.TrimSpace(body.Name)
+	if body.Name == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Name is required", http.StatusBadRequest)
+		return
+	}
+
+	body.UserId = ctx.GetHeader("sub")
+
+	folder, err := h.UseCase.BookmarkFolderRepo.Create(ctx, body)
+	if h.HandleDbError(ctx, err, "Error creating bookmark folder") {
+		return
+	}
+
+	ctx.JSON(200, folder)
+}
+
+// GetBookmarkFolders godoc
+// @Router /bookmark/folder/list [get]
+// @Summary Get the bookmark folders
+// @Description Get the bookmark folders of the user
+// @Security BearerAuth
+// @Tags bookmark
+// @Accept  json
+// @Produce  json
+// @Param page query number true "page"
+// @Param limit query number true "limit"
+// @Success 200 {object} entity.BookmarkFolderList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetBookmarkFolders(ctx *gin.Context) {
+	var (
+		req entity.GetListFilter
+	)
+
+	page := ctx.DefaultQuery("page", "1")
+	limit := ctx.DefaultQuery("limit", "10")
+
+	req.Page, _ = strconv.Atoi(page)
+	req.Limit, _ = strconv.Atoi(limit)
+	req.Filters = append(req.Filters, entity.Filter{
+		Column: "user_id",
+		Type:   "eq",
+		Value:  ctx.GetHeader("sub"),
+	})
+
+	req.OrderBy = append(req.OrderBy, entity.OrderBy{
+		Column: "name",
+		Order:  "asc",
+	})
+
+	folders, err := h.UseCase.BookmarkFolderRepo.GetList(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting bookmark folders") {
+		return
+	}
+
+	ctx.JSON(200, folders)
+}
+
+// UpdateBookmarkFolder godoc
+// @Router /bookmark/folder [put]
+// @Summary Rename a bookmark folder
+// @Description Rename a bookmark folder
+// @Security BearerAuth
+// @Tags bookmark
+// @Accept  json
+// @Produce  json
+// @Param folder body entity.BookmarkFolder true "Folder"
+// @Success 200 {object} entity.BookmarkFolder
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+func (h *Handler) UpdateBookmarkFolder(ctx *gin.Context) {
+	var (
+		body entity.BookmarkFolder
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	body.Name = strings.TrimSpace(body.Name)
+	if body.Name == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Name is required", http.StatusBadRequest)
+		return
+	}
+
+	folder, err := h.UseCase.BookmarkFolderRepo.Update(ctx, body)
+	if h.HandleDbError(ctx, err, "Error updating bookmark folder") {
+		return
+	}
+
+	ctx.JSON(200, folder)
+}
+
+// DeleteBookmarkFolder godoc
+// @Router /bookmark/folder/{id} [delete]
+// @Summary Delete a bookmark folder
+// @Description Bookmarks of the folder are kept without a folder
+// @Security BearerAuth
+// @Tags bookmark
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Folder ID"
+// @Success 200 {object} entity.SuccessResponse
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+func (h *Handler) DeleteBookmarkFolder(ctx *gin.Context) {
+	err := h.UseCase.BookmarkFolderRepo.Delete(ctx, entity.Id{ID: ctx.Param("id")})
+	if h.HandleDbError(ctx, err, "Error deleting bookmark folder") {
+		return
+	}
+
+	ctx.JSON(200, entity.SuccessResponse{
+		Message: "Bookmark folder deleted successfully",
+	})
+}
+
+// BookmarkFolderOwner -.
+func (h *Handler) BookmarkFolderOwner(id IDSource) OwnerResolver {
+	return func(ctx *gin.Context) (string, error) {
+		folder, err := h.UseCase.BookmarkFolderRepo.GetSingle(ctx, entity.Id{ID: id(ctx)})
+		if err != nil {
+			return "", err
+		}
+
+		return folder.UserId, nil
+	}
+}
+
+// checkBookmarkFolder makes sure a bookmark goes only to a folder of the requesting user.
+func (h *Handler) checkBookmarkFolder(ctx *gin.Context, folderID string) bool {
+	if folderID == "" {
+		return true
+	}
+
+	folder, err := h.UseCase.BookmarkFolderRepo.GetSingle(ctx, entity.Id{ID: folderID})
+	if h.HandleDbError(ctx, err, "Error getting bookmark folder") {
+		return false
+	}
+
+	if folder.UserId != ctx.GetHeader("sub") {
+		h.ReturnError(ctx, config.ErrorForbidden, "You have no access to the folder", http.StatusForbidden)
+		return false
+	}
+
+	return true
+}
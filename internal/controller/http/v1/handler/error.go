@@ -68,6 +68,7 @@ func (h Handler) HandleDbError(c *gin.Context, err error, message string) bool {
 				Message: strings.TrimPrefix(err.Error(), "BAD_REQUEST"),
 				Code:    config.ErrorBadRequest,
 			}
+			statusCode = http.StatusBadRequest
 		} else {
 			// General PostgreSQL error
 			errorResponse = entity.ErrorResponse{
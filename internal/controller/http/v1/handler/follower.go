@@ -7,6 +7,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/golanguzb70/udevslabs-twitter/config"
 	"github.com/golanguzb70/udevslabs-twitter/internal/entity"
+	"github.com/golanguzb70/udevslabs-twitter/internal/worker"
 	"github.com/jackc/pgx/v4"
 )
 
@@ -96,6 +97,16 @@ func (h *Handler) FollowUnfollow(ctx *gin.Context) {
 		}
 	}
 
+	// the timeline is rebuilt without the unfollowed account on the next read
+	if follower.UnFollowed {
+		err = h.RedisClient.Del(ctx, worker.HomeTimelineKey(body.FollowerId)).Err()
+	} else {
+		err = h.backfillHomeTimeline(ctx, follower)
+	}
+	if err != nil {
+		h.Logger.Error(err, "Error updating home timeline")
+	}
+
 	ctx.JSON(200, follower)
 }
 
@@ -103,12 +114,15 @@ func (h *Handler) FollowUnfollow(ctx *gin.Context) {
 // @Router /follower/list [get]
 // @Summary Get a list of followers
 // @Description Get a list of followers
+// @Description Latest followers first. Pass next_cursor or prev_cursor of a page as cursor to get the neighbouring page, page is ignored then.
 // @Security BearerAuth
 // @Tags follower
 // @Accept  json
 // @Produce  json
-// @Param page query number true "page"
+// @Param page query number false "page"
 // @Param limit query number true "limit"
+// @Param cursor query string false "cursor"
+// @Param skip_count query bool false "skip_count"
 // @Param following_id query string false "following_id"
 // @Param search query string false "search"
 // @Success 200 {object} entity.UserList
@@ -134,6 +148,8 @@ func (h *Handler) GetFollowers(ctx *gin.Context) {
 
 	req.Page, _ = strconv.Atoi(page)
 	req.Limit, _ = strconv.Atoi(limit)
+	req.Cursor = ctx.Query("cursor")
+	req.SkipCount = ctx.Query("skip_count") == "true"
 	if search != "" {
 		req.Filters = append(req.Filters,
 			entity.Filter{
@@ -155,7 +171,7 @@ func (h *Handler) GetFollowers(ctx *gin.Context) {
 	}
 
 	req.OrderBy = append(req.OrderBy, entity.OrderBy{
-		Column: "created_at",
+		Column: "f.created_at",
 		Order:  "desc",
 	})
 
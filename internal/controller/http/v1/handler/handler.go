@@ -1,24 +1,36 @@
 package handler
 
 import (
+	"github.com/casbin/casbin"
 	rediscache "github.com/golanguzb70/redis-cache"
 	"github.com/golanguzb70/udevslabs-twitter/config"
 	"github.com/golanguzb70/udevslabs-twitter/internal/usecase"
+	"github.com/golanguzb70/udevslabs-twitter/internal/worker"
+	"github.com/golanguzb70/udevslabs-twitter/pkg/jwt"
 	"github.com/golanguzb70/udevslabs-twitter/pkg/logger"
+	"github.com/redis/go-redis/v9"
 )
 
 type Handler struct {
-	Logger  *logger.Logger
-	Config  *config.Config
-	UseCase *usecase.UseCase
-	Redis   rediscache.RedisCache
+	Logger      *logger.Logger
+	Config      *config.Config
+	UseCase     *usecase.UseCase
+	Redis       rediscache.RedisCache
+	RedisClient *redis.Client
+	KeyRing     *jwt.KeyRing
+	Enforcer    *casbin.SyncedEnforcer
+	FanOut      *worker.TimelineFanOut
 }
 
-func NewHandler(l *logger.Logger, c *config.Config, useCase *usecase.UseCase, redis rediscache.RedisCache) *Handler {
+func NewHandler(l *logger.Logger, c *config.Config, useCase *usecase.UseCase, redisCache rediscache.RedisCache, redisClient *redis.Client, keyRing *jwt.KeyRing, enforcer *casbin.SyncedEnforcer, fanOut *worker.TimelineFanOut) *Handler {
 	return &Handler{
-		Logger:  l,
-		Config:  c,
-		UseCase: useCase,
-		Redis:   redis,
+		Logger:      l,
+		Config:      c,
+		UseCase:     useCase,
+		Redis:       redisCache,
+		RedisClient: redisClient,
+		KeyRing:     keyRing,
+		Enforcer:    enforcer,
+		FanOut:      fanOut,
 	}
 }
@@ -0,0 +1,189 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golanguzb70/udevslabs-twitter/config"
+	"github.com/golanguzb70/udevslabs-twitter/internal/entity"
+)
+
+// StartImpersonation godoc
+// @Router /impersonation [post]
+// @Summary Impersonate a user
+// @Description Creates a session of the user for support staff, the access token carries the admin in the act claim.
+// @Description The session can't be refreshed, it ends after the configured ttl or on /auth/logout.
+// @Description Responses made with it have the X-Impersonated-By header and every request is written to the audit log.
+// @Description Passwords, emails, mfa and tokens can't be changed while impersonating.
+// @Security BearerAuth
+// @Tags impersonation
+// @Accept  json
+// @Produce  json
+// @Param body body entity.ImpersonationRequest true "Target user"
+// @Success 200 {object} entity.User
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+func (h *Handler) StartImpersonation(ctx *gin.Context) {
+	var (
+		body entity.ImpersonationRequest
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	body.Reason = strings.TrimSpace(body.Reason)
+	if body.UserID == "" || body.Reason == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "user_id and reason are required", http.StatusBadRequest)
+		return
+	}
+
+	actorID := ctx.GetHeader("sub")
+	if ctx.GetHeader("token_id") != "" || ctx.GetHeader("act_sub") != "" {
+		h.ReturnError(ctx, config.ErrorForbidden, "Impersonation requires a regular admin session", http.StatusForbidden)
+		return
+	}
+
+	if body.UserID == actorID {
+		h.ReturnError(ctx, config.ErrorBadRequest, "You can't impersonate yourself", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.UseCase.UserRepo.GetSingle(ctx, entity.UserSingleRequest{ID: body.UserID})
+	if h.HandleDbError(ctx, err, "Error getting user") {
+		return
+	}
+
+	// only superadmins may act as other admins
+	if user.UserType != "user" && ctx.GetHeader("user_role") != "superadmin" {
+		h.ReturnError(ctx, config.ErrorForbidden, "You can't impersonate an admin", http.StatusForbidden)
+		return
+	}
+
+	session, err := h.UseCase.SessionRepo.Create(ctx, entity.Session{
+		UserID:         user.ID,
+		IPAddress:      ctx.ClientIP(),
+		ExpiresAt:      time.Now().UTC().Add(h.Config.Impersonation.TTL).Format(time.RFC3339),
+		UserAgent:      ctx.Request.UserAgent(),
+		IsActive:       true,
+		LastActiveAt:   time.Now().UTC().Format(time.RFC3339),
+		Platform:       ctx.GetHeader("platform"),
+		ImpersonatorID: actorID,
+	})
+	if h.HandleDbError(ctx, err, "Error creating session") {
+		return
+	}
+
+	user.AccessToken, _, err = h.generateAccessToken(user, session)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorInternalServer, "Error generating access token", http.StatusInternalServerError)
+		return
+	}
+
+	_, err = h.UseCase.ImpersonationAuditLogRepo.Create(ctx, entity.ImpersonationAuditLog{
+		SessionID:  session.ID,
+		ActorID:    actorID,
+		UserID:     user.ID,
+		Method:     ctx.Request.Method,
+		Path:       ctx.Request.URL.Path,
+		StatusCode: http.StatusOK,
+		IPAddress:  ctx.ClientIP(),
+		Details:    body.Reason,
+	})
+	if h.HandleDbError(ctx, err, "Error writing audit log") {
+		return
+	}
+
+	ctx.JSON(200, gin.H{
+		"user":    user,
+		"session": session,
+	})
+}
+
+// GetImpersonationAuditLogs godoc
+// @Router /impersonation/audit [get]
+// @Summary Get the impersonation audit log
+// @Description Requests made with impersonation sessions, newest first
+// @Security BearerAuth
+// @Tags impersonation
+// @Accept  json
+// @Produce  json
+// @Param page query number true "page"
+// @Param limit query number true "limit"
+// @Param session_id query string false "session_id"
+// @Param actor_id query string false "actor_id"
+// @Param user_id query string false "user_id"
+// @Success 200 {object} entity.ImpersonationAuditLogList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetImpersonationAuditLogs(ctx *gin.Context) {
+	var (
+		req entity.GetListFilter
+	)
+
+	page := ctx.DefaultQuery("page", "1")
+	limit := ctx.DefaultQuery("limit", "10")
+
+	req.Page, _ = strconv.Atoi(page)
+	req.Limit, _ = strconv.Atoi(limit)
+
+	for _, column := range []string{"session_id", "actor_id", "user_id"} {
+		if value := ctx.Query(column); value != "" {
+			req.Filters = append(req.Filters, entity.Filter{
+				Column: column,
+				Type:   "eq",
+				Value:  value,
+			})
+		}
+	}
+
+	req.OrderBy = append(req.OrderBy, entity.OrderBy{
+		Column: "created_at",
+		Order:  "desc",
+	})
+
+	logs, err := h.UseCase.ImpersonationAuditLogRepo.GetList(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting audit log") {
+		return
+	}
+
+	ctx.JSON(200, logs)
+}
+
+// NotImpersonated rejects the request if it is made with an impersonation session,
+// routes changing credentials of the user declare it.
+func (h *Handler) NotImpersonated() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if ctx.GetHeader("act_sub") != "" {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, entity.ErrorResponse{
+				Message: "Not allowed while impersonating a user",
+				Code:    config.ErrorForbidden,
+			})
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// auditImpersonation writes a request made with an impersonation session to the audit log,
+// it runs after the handler so the status code is known.
+func (h *Handler) auditImpersonation(ctx *gin.Context, session entity.Session) {
+	_, err := h.UseCase.ImpersonationAuditLogRepo.Create(ctx, entity.ImpersonationAuditLog{
+		SessionID:  session.ID,
+		ActorID:    session.ImpersonatorID,
+		UserID:     session.UserID,
+		Method:     ctx.Request.Method,
+		Path:       ctx.Request.URL.Path,
+		StatusCode: ctx.Writer.Status(),
+		IPAddress:  ctx.ClientIP(),
+		Details:    ctx.Request.URL.RawQuery,
+	})
+	if err != nil {
+		h.Logger.Error(err, "Error writing impersonation audit log")
+	}
+}
@@ -0,0 +1,538 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golanguzb70/udevslabs-twitter/config"
+	"github.com/golanguzb70/udevslabs-twitter/internal/entity"
+	"github.com/golanguzb70/udevslabs-twitter/pkg/etc"
+	"github.com/golanguzb70/udevslabs-twitter/pkg/hash"
+	"github.com/golanguzb70/udevslabs-twitter/pkg/totp"
+)
+
+const (
+	_recoveryCodesCount = 10
+	_maxMfaAttempts     = 5
+)
+
+// MfaEnrollPending godoc
+// @Router /auth/mfa/enroll [post]
+// @Summary Enroll two-factor authentication during login
+// @Description Generates a TOTP secret for users who must enroll before they can log in.
+// @Description The mfa_token is the one returned by login.
+// @Tags auth
+// @Accept  json
+// @Produce  json
+// @Param body body entity.MfaEnrollRequest true "Mfa token"
+// @Success 200 {object} entity.MfaEnrollResponse
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+func (h *Handler) MfaEnrollPending(ctx *gin.Context) {
+	var (
+		body entity.MfaEnrollRequest
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	userID, _, ok := h.parseMfaToken(ctx, body.MfaToken)
+	if !ok {
+		return
+	}
+
+	user, err := h.UseCase.UserRepo.GetSingle(ctx, entity.UserSingleRequest{ID: userID})
+	if h.HandleDbError(ctx, err, "Error getting user") {
+		return
+	}
+
+	h.enrollTotp(ctx, user)
+}
+
+// MfaVerify godoc
+// @Router /auth/mfa/verify [post]
+// @Summary Second login step
+// @Description Verifies a TOTP code or a recovery code for the mfa_token returned by login and creates a session.
+// @Description If the user is finishing enrollment, the code activates two-factor authentication and recovery codes are returned.
+// @Tags auth
+// @Accept  json
+// @Produce  json
+// @Param body body entity.MfaVerifyRequest true "Mfa verification"
+// @Success 200 {object} entity.User
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+func (h *Handler) MfaVerify(ctx *gin.Context) {
+	var (
+		body entity.MfaVerifyRequest
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	userID, platform, ok := h.parseMfaToken(ctx, body.MfaToken)
+	if !ok {
+		return
+	}
+
+	user, err := h.UseCase.UserRepo.GetSingle(ctx, entity.UserSingleRequest{ID: userID})
+	if h.HandleDbError(ctx, err, "Error getting user") {
+		return
+	}
+
+	if !h.checkMfaAttempts(ctx, user.ID) {
+		return
+	}
+
+	var recoveryCodes []string
+
+	switch {
+	case !user.TotpEnabled:
+		if user.TotpSecret == "" {
+			h.ReturnError(ctx, config.ErrorMfaRequired, "Two-factor authentication is not enrolled", http.StatusBadRequest)
+			return
+		}
+
+		if !h.checkTotp(ctx, user, body.Code) {
+			h.mfaFailed(ctx)
+			return
+		}
+
+		recoveryCodes, ok = h.activateTotp(ctx, user)
+		if !ok {
+			return
+		}
+	case body.RecoveryCode != "":
+		rows, err := h.UseCase.MfaRecoveryCodeRepo.UpdateField(ctx, entity.UpdateFieldRequest{
+			Filter: []entity.Filter{
+				{Column: "user_id", Type: "eq", Value: user.ID},
+				{Column: "code_hash", Type: "eq", Value: hash.HashToken(etc.NormalizeRecoveryCode(body.RecoveryCode))},
+				{Column: "is_used", Type: "eq", Value: "false"},
+			},
+			Items: []entity.UpdateFieldItem{
+				{Column: "is_used", Value: true},
+				{Column: "updated_at", Value: "now()"},
+			},
+		})
+		if h.HandleDbError(ctx, err, "Error using recovery code") {
+			return
+		}
+
+		if rows.RowsEffected == 0 {
+			h.mfaFailed(ctx)
+			return
+		}
+	default:
+		if !h.checkTotp(ctx, user, body.Code) {
+			h.mfaFailed(ctx)
+			return
+		}
+	}
+
+	h.resetMfaAttempts(ctx, user.ID)
+
+	user, session, err := h.createSession(ctx, user, platform)
+	if h.HandleDbError(ctx, err, "Error while creating new session") {
+		return
+	}
+
+	response := gin.H{
+		"user":    user,
+		"session": session,
+	}
+
+	if len(recoveryCodes) > 0 {
+		response["recovery_codes"] = recoveryCodes
+	}
+
+	ctx.JSON(200, response)
+}
+
+// MfaEnroll godoc
+// @Router /mfa/enroll [post]
+// @Summary Enroll two-factor authentication
+// @Description Generates a new TOTP secret, two-factor authentication is enabled after /mfa/activate
+// @Security BearerAuth
+// @Tags mfa
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} entity.MfaEnrollResponse
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) MfaEnroll(ctx *gin.Context) {
+	user, err := h.UseCase.UserRepo.GetSingle(ctx, entity.UserSingleRequest{ID: ctx.GetHeader("sub")})
+	if h.HandleDbError(ctx, err, "Error getting user") {
+		return
+	}
+
+	h.enrollTotp(ctx, user)
+}
+
+// MfaActivate godoc
+// @Router /mfa/activate [post]
+// @Summary Activate two-factor authentication
+// @Description Confirms the enrolled TOTP secret with a code and returns one-time recovery codes
+// @Security BearerAuth
+// @Tags mfa
+// @Accept  json
+// @Produce  json
+// @Param body body entity.MfaCodeRequest true "TOTP code"
+// @Success 200 {object} entity.MfaRecoveryCodesResponse
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) MfaActivate(ctx *gin.Context) {
+	var (
+		body entity.MfaCodeRequest
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	user, err := h.UseCase.UserRepo.GetSingle(ctx, entity.UserSingleRequest{ID: ctx.GetHeader("sub")})
+	if h.HandleDbError(ctx, err, "Error getting user") {
+		return
+	}
+
+	if user.TotpEnabled {
+		h.ReturnError(ctx, config.ErrorConflict, "Two-factor authentication is already enabled", http.StatusBadRequest)
+		return
+	}
+
+	if user.TotpSecret == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Two-factor authentication is not enrolled", http.StatusBadRequest)
+		return
+	}
+
+	if !h.checkMfaAttempts(ctx, user.ID) {
+		return
+	}
+
+	if !h.checkTotp(ctx, user, body.Code) {
+		h.mfaFailed(ctx)
+		return
+	}
+
+	h.resetMfaAttempts(ctx, user.ID)
+
+	recoveryCodes, ok := h.activateTotp(ctx, user)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(200, entity.MfaRecoveryCodesResponse{
+		RecoveryCodes: recoveryCodes,
+	})
+}
+
+// MfaRegenerateRecoveryCodes godoc
+// @Router /mfa/recovery-codes [post]
+// @Summary Regenerate recovery codes
+// @Description Replaces all recovery codes, the old ones stop working
+// @Security BearerAuth
+// @Tags mfa
+// @Accept  json
+// @Produce  json
+// @Param body body entity.MfaCodeRequest true "TOTP code"
+// @Success 200 {object} entity.MfaRecoveryCodesResponse
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) MfaRegenerateRecoveryCodes(ctx *gin.Context) {
+	var (
+		body entity.MfaCodeRequest
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	user, err := h.UseCase.UserRepo.GetSingle(ctx, entity.UserSingleRequest{ID: ctx.GetHeader("sub")})
+	if h.HandleDbError(ctx, err, "Error getting user") {
+		return
+	}
+
+	if !user.TotpEnabled {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Two-factor authentication is not enabled", http.StatusBadRequest)
+		return
+	}
+
+	if !h.checkMfaAttempts(ctx, user.ID) {
+		return
+	}
+
+	if !h.checkTotp(ctx, user, body.Code) {
+		h.mfaFailed(ctx)
+		return
+	}
+
+	h.resetMfaAttempts(ctx, user.ID)
+
+	recoveryCodes, err := h.createRecoveryCodes(ctx, user.ID)
+	if h.HandleDbError(ctx, err, "Error creating recovery codes") {
+		return
+	}
+
+	ctx.JSON(200, entity.MfaRecoveryCodesResponse{
+		RecoveryCodes: recoveryCodes,
+	})
+}
+
+// MfaDisable godoc
+// @Router /mfa [delete]
+// @Summary Disable two-factor authentication
+// @Description Disables two-factor authentication, not allowed for user types it is enforced for
+// @Security BearerAuth
+// @Tags mfa
+// @Accept  json
+// @Produce  json
+// @Param body body entity.MfaCodeRequest true "TOTP code"
+// @Success 200 {object} entity.SuccessResponse
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+func (h *Handler) MfaDisable(ctx *gin.Context) {
+	var (
+		body entity.MfaCodeRequest
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	user, err := h.UseCase.UserRepo.GetSingle(ctx, entity.UserSingleRequest{ID: ctx.GetHeader("sub")})
+	if h.HandleDbError(ctx, err, "Error getting user") {
+		return
+	}
+
+	if h.isMfaEnforced(user) {
+		h.ReturnError(ctx, config.ErrorForbidden, "Two-factor authentication is required for your account", http.StatusForbidden)
+		return
+	}
+
+	if !user.TotpEnabled {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Two-factor authentication is not enabled", http.StatusBadRequest)
+		return
+	}
+
+	if !h.checkMfaAttempts(ctx, user.ID) {
+		return
+	}
+
+	if !h.checkTotp(ctx, user, body.Code) {
+		h.mfaFailed(ctx)
+		return
+	}
+
+	h.resetMfaAttempts(ctx, user.ID)
+
+	_, err = h.UseCase.UserRepo.UpdateField(ctx, entity.UpdateFieldRequest{
+		Filter: []entity.Filter{{Column: "id", Type: "eq", Value: user.ID}},
+		Items: []entity.UpdateFieldItem{
+			{Column: "totp_enabled", Value: false},
+			{Column: "totp_secret", Value: nil},
+			{Column: "updated_at", Value: "now()"},
+		},
+	})
+	if h.HandleDbError(ctx, err, "Error disabling two-factor authentication") {
+		return
+	}
+
+	err = h.UseCase.MfaRecoveryCodeRepo.Replace(ctx, entity.MfaRecoveryCodeReplaceRequest{UserID: user.ID})
+	if h.HandleDbError(ctx, err, "Error deleting recovery codes") {
+		return
+	}
+
+	ctx.JSON(200, entity.SuccessResponse{
+		Message: "Two-factor authentication disabled",
+	})
+}
+
+// mfaChallenge answers a successful password check with a short-lived mfa token instead of a session.
+func (h *Handler) mfaChallenge(ctx *gin.Context, user entity.User, platform string) {
+	now := time.Now().UTC()
+	expiresAt := now.Add(config.MfaTokenExpireTime)
+
+	token, err := h.KeyRing.Generate(map[string]interface{}{
+		"sub":         user.ID,
+		"platform":    platform,
+		"mfa_pending": true,
+		"iat":         now.Unix(),
+		"exp":         expiresAt.Unix(),
+	})
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorInternalServer, "Oops, something went wrong!!!", http.StatusInternalServerError)
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, entity.MfaChallengeResponse{
+		MfaToken:           token,
+		EnrollmentRequired: !user.TotpEnabled,
+		ExpiresAt:          expiresAt.Format(time.RFC3339),
+	})
+}
+
+// parseMfaToken validates the mfa token and returns user id and platform from it.
+func (h *Handler) parseMfaToken(ctx *gin.Context, token string) (string, string, bool) {
+	claims, err := h.KeyRing.Parse(token)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorInvalidToken, "Invalid or expired mfa token", http.StatusUnauthorized)
+		return "", "", false
+	}
+
+	pending, _ := claims["mfa_pending"].(bool)
+	userID, _ := claims["sub"].(string)
+	platform, _ := claims["platform"].(string)
+
+	if !pending || userID == "" {
+		h.ReturnError(ctx, config.ErrorInvalidToken, "Invalid or expired mfa token", http.StatusUnauthorized)
+		return "", "", false
+	}
+
+	return userID, platform, true
+}
+
+// enrollTotp stores a new secret for the user and returns it with the otpauth URI.
+func (h *Handler) enrollTotp(ctx *gin.Context, user entity.User) {
+	if user.TotpEnabled {
+		h.ReturnError(ctx, config.ErrorConflict, "Two-factor authentication is already enabled", http.StatusBadRequest)
+		return
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorInternalServer, "Oops, something went wrong!!!", http.StatusInternalServerError)
+		return
+	}
+
+	_, err = h.UseCase.UserRepo.UpdateField(ctx, entity.UpdateFieldRequest{
+		Filter: []entity.Filter{{Column: "id", Type: "eq", Value: user.ID}},
+		Items: []entity.UpdateFieldItem{
+			{Column: "totp_secret", Value: secret},
+			{Column: "updated_at", Value: "now()"},
+		},
+	})
+	if h.HandleDbError(ctx, err, "Error saving totp secret") {
+		return
+	}
+
+	ctx.JSON(200, entity.MfaEnrollResponse{
+		Secret: secret,
+		URI:    totp.URI(h.Config.MFA.Issuer, user.Email, secret),
+	})
+}
+
+// activateTotp enables two-factor authentication and returns fresh recovery codes.
+func (h *Handler) activateTotp(ctx *gin.Context, user entity.User) ([]string, bool) {
+	_, err := h.UseCase.UserRepo.UpdateField(ctx, entity.UpdateFieldRequest{
+		Filter: []entity.Filter{{Column: "id", Type: "eq", Value: user.ID}},
+		Items: []entity.UpdateFieldItem{
+			{Column: "totp_enabled", Value: true},
+			{Column: "updated_at", Value: "now()"},
+		},
+	})
+	if h.HandleDbError(ctx, err, "Error enabling two-factor authentication") {
+		return nil, false
+	}
+
+	recoveryCodes, err := h.createRecoveryCodes(ctx, user.ID)
+	if h.HandleDbError(ctx, err, "Error creating recovery codes") {
+		return nil, false
+	}
+
+	return recoveryCodes, true
+}
+
+// createRecoveryCodes replaces the user's recovery codes, only hashes are stored.
+func (h *Handler) createRecoveryCodes(ctx *gin.Context, userID string) ([]string, error) {
+	var (
+		codes  = make([]string, 0, _recoveryCodesCount)
+		hashes = make([]string, 0, _recoveryCodesCount)
+	)
+
+	for i := 0; i < _recoveryCodesCount; i++ {
+		code, err := etc.GenerateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+
+		codes = append(codes, code)
+		hashes = append(hashes, hash.HashToken(etc.NormalizeRecoveryCode(code)))
+	}
+
+	err := h.UseCase.MfaRecoveryCodeRepo.Replace(ctx, entity.MfaRecoveryCodeReplaceRequest{
+		UserID:     userID,
+		CodeHashes: hashes,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// checkTotp validates the code and makes sure the same code can't be used twice.
+func (h *Handler) checkTotp(ctx *gin.Context, user entity.User, code string) bool {
+	step, ok := totp.Validate(code, user.TotpSecret, time.Now())
+	if !ok {
+		return false
+	}
+
+	// the step is marked as used atomically, codes aren't accepted when that can't be done
+	key := fmt.Sprintf("totp-used-%s-%d", user.ID, step)
+	unused, err := h.RedisClient.SetNX(ctx, key, "1", 3*30*time.Second).Result()
+	if err != nil {
+		h.Logger.Error(err, "Error saving used totp step")
+		return false
+	}
+
+	return unused
+}
+
+// checkMfaAttempts counts the attempt and rejects the request once too many codes were sent.
+// Attempts are counted before the code is checked, so parallel requests can't get past the limit,
+// a correct code resets the count.
+func (h *Handler) checkMfaAttempts(ctx *gin.Context, userID string) bool {
+	attempts, err := h.incrWithWindow(ctx, mfaAttemptsKey(userID), config.MfaTokenExpireTime)
+	if err != nil {
+		h.Logger.Error(err, "Error counting mfa attempts")
+		h.ReturnError(ctx, config.ErrorInternalServer, "Oops, something went wrong!!!", http.StatusInternalServerError)
+		return false
+	}
+
+	if attempts > _maxMfaAttempts {
+		h.ReturnError(ctx, config.ErrorInvalidMfaCode, "Too many wrong codes, try again later", http.StatusTooManyRequests)
+		return false
+	}
+
+	return true
+}
+
+func (h *Handler) mfaFailed(ctx *gin.Context) {
+	h.ReturnError(ctx, config.ErrorInvalidMfaCode, "Incorrect code", http.StatusBadRequest)
+}
+
+func (h *Handler) resetMfaAttempts(ctx *gin.Context, userID string) {
+	err := h.RedisClient.Del(ctx, mfaAttemptsKey(userID)).Err()
+	if err != nil {
+		h.Logger.Error(err, "Error deleting mfa attempts")
+	}
+}
+
+func mfaAttemptsKey(userID string) string {
+	return fmt.Sprintf("mfa-attempts-%s", userID)
+}
+
+// isMfaEnforced reports whether the user's type must use two-factor authentication.
+func (h *Handler) isMfaEnforced(user entity.User) bool {
+	return slices.Contains(h.Config.MFA.EnforcedUserTypes, user.UserType)
+}
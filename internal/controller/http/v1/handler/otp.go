@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golanguzb70/udevslabs-twitter/config"
+	"github.com/golanguzb70/udevslabs-twitter/internal/entity"
+	"github.com/golanguzb70/udevslabs-twitter/pkg/etc"
+	"github.com/golanguzb70/udevslabs-twitter/pkg/hash"
+	"github.com/redis/go-redis/v9"
+)
+
+var errOtpCooldown = errors.New("otp was sent recently")
+
+// otp purposes, every purpose has its own code, so a code can't be used for anything else than it was sent for
+const (
+	_otpVerifyEmail   = "verify"
+	_otpResetPassword = "reset"
+	_otpChangeEmail   = "change"
+)
+
+// ResendOtp godoc
+// @Router /auth/resend-otp [post]
+// @Summary Resend email verification otp
+// @Description Sends a new verification code to a user who has not verified the email yet.
+// @Description A new code can be requested once per cooldown period, the previous code stops working.
+// @Tags auth
+// @Accept  json
+// @Produce  json
+// @Param body body entity.ResendOtpRequest true "Email"
+// @Success 200 {object} entity.SuccessResponse
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 429 {object} entity.ErrorResponse
+func (h *Handler) ResendOtp(ctx *gin.Context) {
+	var (
+		body entity.ResendOtpRequest
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil || body.Email == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	user, err := h.UseCase.UserRepo.GetSingle(ctx, entity.UserSingleRequest{
+		Email: body.Email,
+	})
+	if h.HandleDbError(ctx, err, "Error getting user") {
+		return
+	}
+
+	if user.Status != "inverify" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Email is already verified", http.StatusBadRequest)
+		return
+	}
+
+	err = h.sendOtp(ctx, _otpVerifyEmail, user.Email, etc.GenerateOtpEmailBody)
+	if errors.Is(err, errOtpCooldown) {
+		h.setRetryAfter(ctx, otpCooldownKey(_otpVerifyEmail, user.Email))
+		h.ReturnError(ctx, config.ErrorTooManyRequest, "Otp was sent recently, please wait before requesting a new one", http.StatusTooManyRequests)
+		return
+	}
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorInternalServer, "Error sending OTP", 500)
+		return
+	}
+
+	ctx.JSON(200, entity.SuccessResponse{
+		Message: "Verification code has been sent to your email address",
+	})
+}
+
+// sendOtp generates an otp for the purpose, stores its hash and emails the code using the body generator.
+// It returns errOtpCooldown if a code for the purpose was sent to the email less than OTP.Cooldown ago.
+func (h *Handler) sendOtp(ctx *gin.Context, purpose, email string, generateBody func(otp string) (string, error)) error {
+	ok, err := h.RedisClient.SetNX(ctx, otpCooldownKey(purpose, email), "1", h.Config.OTP.Cooldown).Result()
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return errOtpCooldown
+	}
+
+	otp := etc.GenerateOTP(6)
+
+	err = h.RedisClient.Set(ctx, otpKey(purpose, email), hash.HMAC(otp, h.Config.JWT.Secret), h.Config.OTP.TTL).Err()
+	if err != nil {
+		return err
+	}
+
+	// a new code gets a fresh set of attempts
+	err = h.RedisClient.Del(ctx, otpAttemptsKey(purpose, email)).Err()
+	if err != nil {
+		return err
+	}
+
+	emailBody, err := generateBody(otp)
+	if err != nil {
+		return err
+	}
+
+	return etc.SendEmail(h.Config.Gmail.Host, h.Config.Gmail.Port, h.Config.Gmail.Email, h.Config.Gmail.EmailPass, email, emailBody)
+}
+
+// verifyOtp checks the otp sent to the email for the purpose and writes an error response if it doesn't match.
+// After OTP.MaxAttempts wrong guesses the code is burned and a new one has to be requested.
+// A matching code is deleted, so it can be used only once.
+func (h *Handler) verifyOtp(ctx *gin.Context, purpose, email, otp string) bool {
+	stored, err := h.RedisClient.Get(ctx, otpKey(purpose, email)).Result()
+	if errors.Is(err, redis.Nil) {
+		h.ReturnError(ctx, config.ErrorOtpExpired, "Otp is expired, please request a new one", http.StatusBadRequest)
+		return false
+	}
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorInternalServer, "Ooops, something went wrong", http.StatusInternalServerError)
+		return false
+	}
+
+	if stored != hash.HMAC(otp, h.Config.JWT.Secret) {
+		attempts, err := h.RedisClient.Incr(ctx, otpAttemptsKey(purpose, email)).Result()
+		if err != nil {
+			h.ReturnError(ctx, config.ErrorInternalServer, "Ooops, something went wrong", http.StatusInternalServerError)
+			return false
+		}
+
+		if attempts == 1 {
+			h.RedisClient.Expire(ctx, otpAttemptsKey(purpose, email), h.Config.OTP.TTL)
+		}
+
+		if attempts >= int64(h.Config.OTP.MaxAttempts) {
+			h.RedisClient.Del(ctx, otpKey(purpose, email), otpAttemptsKey(purpose, email))
+			h.ReturnError(ctx, config.ErrorOtpExpired, "Too many wrong attempts, please request a new otp", http.StatusBadRequest)
+			return false
+		}
+
+		h.ReturnError(ctx, config.ErrorInvalidOtp,
+			fmt.Sprintf("Incorrect otp, %d attempts left", int64(h.Config.OTP.MaxAttempts)-attempts), http.StatusBadRequest)
+		return false
+	}
+
+	err = h.RedisClient.Del(ctx, otpKey(purpose, email), otpAttemptsKey(purpose, email)).Err()
+	if err != nil {
+		h.Logger.Error(err, "Error deleting OTP")
+	}
+
+	return true
+}
+
+func otpKey(purpose, email string) string {
+	return fmt.Sprintf("otp-%s-%s", purpose, email)
+}
+
+func otpAttemptsKey(purpose, email string) string {
+	return fmt.Sprintf("otp-attempts-%s-%s", purpose, email)
+}
+
+func otpCooldownKey(purpose, email string) string {
+	return fmt.Sprintf("otp-cooldown-%s-%s", purpose, email)
+}
@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golanguzb70/udevslabs-twitter/config"
+	"github.com/golanguzb70/udevslabs-twitter/internal/entity"
+	"github.com/jackc/pgx/v4"
+)
+
+// OwnerResolver returns the id of the user who owns the resource a request targets.
+type OwnerResolver func(ctx *gin.Context) (string, error)
+
+// IDSource extracts the resource id from a request.
+type IDSource func(ctx *gin.Context) string
+
+// OwnerOrAdmin lets a request through only if the caller owns the resource or is an admin.
+// Routes declare it next to the handler, e.g.
+//
+//	tweet.DELETE("/:id", h.OwnerOrAdmin(h.TweetOwner(PathID("id"))), h.DeleteTweet)
+func (h *Handler) OwnerOrAdmin(resolve OwnerResolver) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if ctx.GetHeader("user_type") == "admin" {
+			ctx.Next()
+			return
+		}
+
+		ownerID, err := resolve(ctx)
+		if err == pgx.ErrNoRows {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, entity.ErrorResponse{
+				Message: "The requested resource was not found.",
+				Code:    config.ErrorNotFound,
+			})
+			return
+		}
+		if err != nil {
+			h.Logger.Error(err, "Error resolving resource owner")
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, entity.ErrorResponse{
+				Message: "Ooops! Something went wrong.",
+				Code:    config.ErrorInternalServer,
+			})
+			return
+		}
+
+		sub := ctx.GetHeader("sub")
+		if sub == "" || ownerID != sub {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, entity.ErrorResponse{
+				Message: "You have no access to the resource",
+				Code:    config.ErrorForbidden,
+			})
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// TweetOwner -.
+func (h *Handler) TweetOwner(id IDSource) OwnerResolver {
+	return func(ctx *gin.Context) (string, error) {
+		tweet, err := h.UseCase.TweetRepo.GetSingle(ctx, entity.Id{ID: id(ctx)})
+		if err != nil {
+			return "", err
+		}
+
+		return tweet.Owner.ID, nil
+	}
+}
+
+// SessionOwner -.
+func (h *Handler) SessionOwner(id IDSource) OwnerResolver {
+	return func(ctx *gin.Context) (string, error) {
+		session, err := h.UseCase.SessionRepo.GetSingle(ctx, entity.Id{ID: id(ctx)})
+		if err != nil {
+			return "", err
+		}
+
+		return session.UserID, nil
+	}
+}
+
+// TokenOwner -.
+func (h *Handler) TokenOwner(id IDSource) OwnerResolver {
+	return func(ctx *gin.Context) (string, error) {
+		pat, err := h.UseCase.PersonalAccessTokenRepo.GetSingle(ctx, entity.PersonalAccessTokenSingleRequest{ID: id(ctx)})
+		if err != nil {
+			return "", err
+		}
+
+		return pat.UserID, nil
+	}
+}
+
+// UserSelf treats a user as the owner of their own account, a missing id means the caller.
+func (h *Handler) UserSelf(id IDSource) OwnerResolver {
+	return func(ctx *gin.Context) (string, error) {
+		if userID := id(ctx); userID != "" {
+			return userID, nil
+		}
+
+		return ctx.GetHeader("sub"), nil
+	}
+}
+
+// PathID reads the id from a path parameter.
+func PathID(name string) IDSource {
+	return func(ctx *gin.Context) string {
+		return ctx.Param(name)
+	}
+}
+
+// BodyID reads the id from a field of the JSON body, the body is restored for the handler.
+func BodyID(field string) IDSource {
+	return func(ctx *gin.Context) string {
+		if ctx.Request.Body == nil {
+			return ""
+		}
+
+		data, err := io.ReadAll(ctx.Request.Body)
+		ctx.Request.Body = io.NopCloser(bytes.NewReader(data))
+		if err != nil {
+			return ""
+		}
+
+		var body map[string]interface{}
+		if json.Unmarshal(data, &body) != nil {
+			return ""
+		}
+
+		id, _ := body[field].(string)
+
+		return id
+	}
+}
@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golanguzb70/udevslabs-twitter/config"
+	"github.com/golanguzb70/udevslabs-twitter/internal/entity"
+)
+
+// GetPolicies godoc
+// @Router /policy/list [get]
+// @Summary Get access control policies
+// @Description Returns every p (sub, obj, act) and g (user, role) rule. Only superadmins can manage policies.
+// @Security BearerAuth
+// @Tags policy
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} entity.PolicyList
+// @Failure 403 {object} entity.ErrorResponse
+func (h *Handler) GetPolicies(ctx *gin.Context) {
+	ctx.JSON(200, entity.PolicyList{
+		Policies:         h.Enforcer.GetPolicy(),
+		GroupingPolicies: h.Enforcer.GetGroupingPolicy(),
+	})
+}
+
+// AddPolicy godoc
+// @Router /policy [post]
+// @Summary Add an access control rule
+// @Description Adds a p rule like ["user", "/v1/tweet/*", "GET|POST"] or a g rule like ["admin", "user"].
+// @Description Every instance reloads its policies after the change.
+// @Security BearerAuth
+// @Tags policy
+// @Accept  json
+// @Produce  json
+// @Param body body entity.PolicyRule true "Rule"
+// @Success 200 {object} entity.SuccessResponse
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) AddPolicy(ctx *gin.Context) {
+	var (
+		body entity.PolicyRule
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil || !validPolicyRule(body) {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid policy rule", http.StatusBadRequest)
+		return
+	}
+
+	params := make([]interface{}, len(body.Rule))
+	for i, value := range body.Rule {
+		params[i] = value
+	}
+
+	added, err := h.updatePolicy(func() bool {
+		if body.Ptype == "g" {
+			return h.Enforcer.AddGroupingPolicy(params...)
+		}
+
+		return h.Enforcer.AddPolicy(params...)
+	})
+	if err != nil {
+		h.Logger.Error(err, "Error saving policy")
+		h.ReturnError(ctx, config.ErrorInternalServer, "Error saving policy", http.StatusInternalServerError)
+		return
+	}
+
+	if !added {
+		h.ReturnError(ctx, config.ErrorConflict, "Policy rule already exists", http.StatusBadRequest)
+		return
+	}
+
+	ctx.JSON(200, entity.SuccessResponse{
+		Message: "Policy rule added successfully",
+	})
+}
+
+// RemovePolicy godoc
+// @Router /policy [delete]
+// @Summary Remove an access control rule
+// @Description Removes a p or g rule, every instance reloads its policies after the change.
+// @Security BearerAuth
+// @Tags policy
+// @Accept  json
+// @Produce  json
+// @Param body body entity.PolicyRule true "Rule"
+// @Success 200 {object} entity.SuccessResponse
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+func (h *Handler) RemovePolicy(ctx *gin.Context) {
+	var (
+		body entity.PolicyRule
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil || !validPolicyRule(body) {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid policy rule", http.StatusBadRequest)
+		return
+	}
+
+	params := make([]interface{}, len(body.Rule))
+	for i, value := range body.Rule {
+		params[i] = value
+	}
+
+	removed, err := h.updatePolicy(func() bool {
+		if body.Ptype == "g" {
+			return h.Enforcer.RemoveGroupingPolicy(params...)
+		}
+
+		return h.Enforcer.RemovePolicy(params...)
+	})
+	if err != nil {
+		h.Logger.Error(err, "Error removing policy")
+		h.ReturnError(ctx, config.ErrorInternalServer, "Error removing policy", http.StatusInternalServerError)
+		return
+	}
+
+	if !removed {
+		h.ReturnError(ctx, config.ErrorNotFound, "Policy rule not found", http.StatusNotFound)
+		return
+	}
+
+	ctx.JSON(200, entity.SuccessResponse{
+		Message: "Policy rule removed successfully",
+	})
+}
+
+// updatePolicy runs an enforcer change, casbin panics when the adapter fails to save it.
+// The model is changed before the adapter is called, so it is reloaded to drop the unsaved change.
+func (h *Handler) updatePolicy(update func() bool) (ok bool, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+
+			if loadErr := h.Enforcer.LoadPolicy(); loadErr != nil {
+				h.Logger.Error(loadErr, "Error reloading policy")
+			}
+		}
+	}()
+
+	return update(), nil
+}
+
+// validPolicyRule checks the rule fits rbac.conf, act is used with regexMatch so it has to compile.
+func validPolicyRule(rule entity.PolicyRule) bool {
+	for _, value := range rule.Rule {
+		if strings.TrimSpace(value) == "" || strings.Contains(value, ",") {
+			return false
+		}
+	}
+
+	switch rule.Ptype {
+	case "p":
+		if len(rule.Rule) != 3 {
+			return false
+		}
+
+		_, err := regexp.Compile(rule.Rule[2])
+
+		return err == nil
+	case "g":
+		return len(rule.Rule) == 2
+	default:
+		return false
+	}
+}
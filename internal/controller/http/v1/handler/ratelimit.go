@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golanguzb70/udevslabs-twitter/config"
+	"github.com/golanguzb70/udevslabs-twitter/internal/entity"
+	"github.com/golanguzb70/udevslabs-twitter/pkg/ratelimit"
+)
+
+// RateLimitMiddleware limits requests per user, or per client IP for unauthorized ones, with the
+// rules of the rate_limit config. It must run after AuthMiddleware, which sets sub and user_role.
+func (h *Handler) RateLimitMiddleware() gin.HandlerFunc {
+	limiter := ratelimit.New(h.RedisClient, "rate-limit-")
+
+	return func(c *gin.Context) {
+		if !h.Config.RateLimit.Enabled {
+			c.Next()
+			return
+		}
+
+		index, rule, ok := matchRateLimitRule(h.Config.RateLimit.Rules, c.FullPath(), c.Request.Method)
+		if !ok {
+			c.Next()
+			return
+		}
+
+		role := c.GetHeader("user_role")
+		limit := rule.Limit
+		if roleLimit, ok := rule.Roles[role]; ok {
+			limit = roleLimit
+		}
+		if limit <= 0 {
+			c.Next()
+			return
+		}
+
+		window := rule.Window
+		if window <= 0 {
+			window = time.Minute
+		}
+
+		subject := "ip-" + c.ClientIP()
+		if sub := c.GetHeader("sub"); role != "unauthorized" && sub != "" {
+			subject = "user-" + sub
+		}
+
+		result, err := limiter.Allow(c, fmt.Sprintf("%d-%s", index, subject), limit, window)
+		if err != nil {
+			// a redis outage shouldn't take the api down with it
+			h.Logger.Error(err, "Error checking rate limit")
+			c.Next()
+			return
+		}
+
+		reset := strconv.Itoa(int(math.Ceil(result.Reset.Seconds())))
+		c.Header("RateLimit-Policy", fmt.Sprintf("%d;w=%d", limit, int(window.Seconds())))
+		c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", reset)
+
+		if !result.Allowed {
+			c.Header("Retry-After", reset)
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, entity.ErrorResponse{
+				Message: "Too many requests, try again later",
+				Code:    config.ErrorTooManyRequest,
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// matchRateLimitRule returns the first rule matching the route and its index, the index
+// is part of the redis key so every rule has its own window.
+func matchRateLimitRule(rules []config.RateLimitRule, path, method string) (int, config.RateLimitRule, bool) {
+	for i, rule := range rules {
+		if rule.Method != "" && !containsMethod(rule.Method, method) {
+			continue
+		}
+
+		if prefix, ok := strings.CutSuffix(rule.Path, "*"); ok {
+			if strings.HasPrefix(path, prefix) {
+				return i, rule, true
+			}
+		} else if path == rule.Path {
+			return i, rule, true
+		}
+	}
+
+	return 0, config.RateLimitRule{}, false
+}
+
+func containsMethod(methods, method string) bool {
+	for _, m := range strings.Split(methods, "|") {
+		if strings.EqualFold(strings.TrimSpace(m), method) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -1,7 +1,9 @@
 package handler
 
 import (
+	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golanguzb70/udevslabs-twitter/config"
@@ -19,6 +21,7 @@ import (
 // @Param id path string true "Session ID"
 // @Success 200 {object} entity.Session
 // @Failure 400 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
 func (h *Handler) GetSession(ctx *gin.Context) {
 	var (
 		req entity.Id
@@ -31,6 +34,8 @@ func (h *Handler) GetSession(ctx *gin.Context) {
 		return
 	}
 
+	session.IsCurrent = session.ID == ctx.GetHeader("session_id")
+
 	ctx.JSON(200, session)
 }
 
@@ -38,12 +43,15 @@ func (h *Handler) GetSession(ctx *gin.Context) {
 // @Router /session/list [get]
 // @Summary Get a list of users
 // @Description Get a list of users
+// @Description Pass next_cursor or prev_cursor of a page as cursor to get the neighbouring page, page is ignored then.
 // @Security BearerAuth
 // @Tags session
 // @Accept  json
 // @Produce  json
-// @Param page query number true "page"
+// @Param page query number false "page"
 // @Param limit query number true "limit"
+// @Param cursor query string false "cursor"
+// @Param skip_count query bool false "skip_count"
 // @Param user_id query string false "user_id"
 // @Success 200 {object} entity.SessionList
 // @Failure 400 {object} entity.ErrorResponse
@@ -62,6 +70,8 @@ func (h *Handler) GetSessions(ctx *gin.Context) {
 
 	req.Page, _ = strconv.Atoi(page)
 	req.Limit, _ = strconv.Atoi(limit)
+	req.Cursor = ctx.Query("cursor")
+	req.SkipCount = ctx.Query("skip_count") == "true"
 	req.Filters = append(req.Filters,
 		entity.Filter{
 			Column: "user_id",
@@ -80,6 +90,10 @@ func (h *Handler) GetSessions(ctx *gin.Context) {
 		return
 	}
 
+	for i := range sessions.Items {
+		sessions.Items[i].IsCurrent = sessions.Items[i].ID == ctx.GetHeader("session_id")
+	}
+
 	ctx.JSON(200, sessions)
 }
 
@@ -124,6 +138,7 @@ func (h *Handler) UpdateSession(ctx *gin.Context) {
 // @Param id path string true "Session ID"
 // @Success 200 {object} entity.SuccessResponse
 // @Failure 400 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
 func (h *Handler) DeleteSession(ctx *gin.Context) {
 	var (
 		req entity.Id
@@ -140,3 +155,72 @@ func (h *Handler) DeleteSession(ctx *gin.Context) {
 		Message: "Session deleted successfully",
 	})
 }
+
+// RevokeOtherSessions godoc
+// @Router /session/revoke-others [post]
+// @Summary Sign out everywhere else
+// @Description Deactivates all sessions of the user except the current one
+// @Security BearerAuth
+// @Tags session
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} entity.RowsEffected
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) RevokeOtherSessions(ctx *gin.Context) {
+	sessionID := ctx.GetHeader("session_id")
+	if sessionID == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid session ID", 400)
+		return
+	}
+
+	rows, err := h.UseCase.SessionRepo.UpdateField(ctx, entity.UpdateFieldRequest{
+		Filter: []entity.Filter{
+			{Column: "user_id", Type: "eq", Value: ctx.GetHeader("sub")},
+			{Column: "id", Type: "neq", Value: sessionID},
+			{Column: "is_active", Type: "eq", Value: "true"},
+		},
+		Items: []entity.UpdateFieldItem{
+			{Column: "is_active", Value: false},
+			{Column: "updated_at", Value: "now()"},
+		},
+	})
+	if h.HandleDbError(ctx, err, "Error revoking sessions") {
+		return
+	}
+
+	ctx.JSON(200, rows)
+}
+
+// touchSession records the last activity and ip address of the session. The write is skipped
+// while the stored value is fresh, and a redis key makes sure only one instance does it per
+// SessionActivity.UpdateInterval.
+func (h *Handler) touchSession(ctx *gin.Context, session entity.Session) {
+	interval := h.Config.SessionActivity.UpdateInterval
+
+	lastActiveAt, err := time.Parse(time.RFC3339, session.LastActiveAt)
+	if err == nil && time.Since(lastActiveAt) < interval && session.IPAddress == ctx.ClientIP() {
+		return
+	}
+
+	ok, err := h.RedisClient.SetNX(ctx, sessionActivityKey(session.ID), "1", interval).Result()
+	if err != nil || !ok {
+		return
+	}
+
+	_, err = h.UseCase.SessionRepo.UpdateField(ctx, entity.UpdateFieldRequest{
+		Filter: []entity.Filter{
+			{Column: "id", Type: "eq", Value: session.ID},
+		},
+		Items: []entity.UpdateFieldItem{
+			{Column: "last_active_at", Value: time.Now().UTC()},
+			{Column: "ip_address", Value: ctx.ClientIP()},
+		},
+	})
+	if err != nil {
+		h.Logger.Error(err, "Error updating session activity")
+	}
+}
+
+func sessionActivityKey(sessionID string) string {
+	return fmt.Sprintf("session-activity-%s", sessionID)
+}
@@ -0,0 +1,302 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golanguzb70/udevslabs-twitter/config"
+	"github.com/golanguzb70/udevslabs-twitter/internal/entity"
+	"github.com/golanguzb70/udevslabs-twitter/internal/worker"
+	"github.com/redis/go-redis/v9"
+)
+
+// GetHomeTimeline godoc
+// @Router /timeline/home [get]
+// @Summary Get the home timeline
+// @Description Published tweets of the followed accounts and of the caller, newest first.
+// @Description Pass next_cursor of a page as cursor to get the next one.
+// @Security BearerAuth
+// @Tags timeline
+// @Accept  json
+// @Produce  json
+// @Param cursor query string false "cursor"
+// @Param limit query number false "limit"
+// @Success 200 {object} entity.Timeline
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetHomeTimeline(ctx *gin.Context) {
+	req := entity.TimelineRequest{
+		UserId: ctx.GetHeader("sub"),
+	}
+
+	req.Limit, _ = strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+	if req.Limit <= 0 || req.Limit > 100 {
+		h.ReturnError(ctx, config.ErrorBadRequest, "limit must be between 1 and 100", http.StatusBadRequest)
+		return
+	}
+
+	if cursor := ctx.Query("cursor"); cursor != "" {
+		before, err := strconv.ParseInt(cursor, 10, 64)
+		if err != nil || before <= 0 {
+			h.ReturnError(ctx, config.ErrorBadRequest, "Invalid cursor", http.StatusBadRequest)
+			return
+		}
+
+		req.Before = before
+	}
+
+	entries, err := h.homeTimeline(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting home timeline") {
+		return
+	}
+
+	response := entity.Timeline{
+		Items: []entity.Tweet{},
+	}
+
+	if len(entries) == req.Limit {
+		response.NextCursor = strconv.FormatInt(entries[len(entries)-1].Score, 10)
+	}
+
+	if len(entries) == 0 {
+		ctx.JSON(200, response)
+		return
+	}
+
+	ids := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		ids = append(ids, entry.TweetId)
+	}
+
+	tweets, err := h.UseCase.TweetRepo.GetList(ctx, entity.GetListFilter{
+		Page:  1,
+		Limit: len(ids),
+		Filters: []entity.Filter{
+			{
+				Column: "tweet.id",
+				Type:   "in",
+				Value:  strings.Join(ids, ","),
+			},
+			{
+				Column: "tweet.status",
+				Type:   "eq",
+				Value:  "published",
+			},
+		},
+	})
+	if h.HandleDbError(ctx, err, "Error getting home timeline") {
+		return
+	}
+
+	byID := make(map[string]entity.Tweet, len(tweets.Items))
+	for _, tweet := range tweets.Items {
+		byID[tweet.Id] = tweet
+	}
+
+	// deleted and unpublished tweets are still cached, they are left out of the page
+	for _, entry := range entries {
+		if tweet, ok := byID[entry.TweetId]; ok {
+			response.Items = append(response.Items, tweet)
+		}
+	}
+
+	if !h.setViewerState(ctx, response.Items) {
+		return
+	}
+
+	ctx.JSON(200, response)
+}
+
+// GetMentionsTimeline godoc
+// @Router /timeline/mentions [get]
+// @Summary Get the mentions timeline
+// @Description Published tweets that mention the caller, newest first.
+// @Description Pass next_cursor or prev_cursor of a page as cursor to get the neighbouring page, page is ignored then.
+// @Security BearerAuth
+// @Tags timeline
+// @Accept  json
+// @Produce  json
+// @Param page query number false "page"
+// @Param limit query number false "limit"
+// @Param cursor query string false "cursor"
+// @Param skip_count query bool false "skip_count"
+// @Success 200 {object} entity.TweetList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetMentionsTimeline(ctx *gin.Context) {
+	var (
+		req entity.GetListFilter
+	)
+
+	page := ctx.DefaultQuery("page", "1")
+	limit := ctx.DefaultQuery("limit", "20")
+
+	req.Page, _ = strconv.Atoi(page)
+	req.Limit, _ = strconv.Atoi(limit)
+	if req.Limit <= 0 || req.Limit > 100 {
+		h.ReturnError(ctx, config.ErrorBadRequest, "limit must be between 1 and 100", http.StatusBadRequest)
+		return
+	}
+
+	req.Cursor = ctx.Query("cursor")
+	req.SkipCount = ctx.Query("skip_count") == "true"
+	req.Filters = append(req.Filters,
+		entity.Filter{
+			Column: "m.user_id",
+			Type:   "eq",
+			Value:  ctx.GetHeader("sub"),
+		},
+		entity.Filter{
+			Column: "tweet.status",
+			Type:   "eq",
+			Value:  "published",
+		},
+	)
+
+	tweets, err := h.UseCase.TweetRepo.GetMentions(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting mentions timeline") {
+		return
+	}
+
+	if !h.setViewerState(ctx, tweets.Items) {
+		return
+	}
+
+	ctx.JSON(200, tweets)
+}
+
+// homeTimeline reads a page of the cached home timeline, rebuilding it when it isn't cached,
+// and merges in the tweets of followed accounts that are fanned out on read.
+func (h *Handler) homeTimeline(ctx context.Context, req entity.TimelineRequest) ([]entity.TimelineEntry, error) {
+	key := worker.HomeTimelineKey(req.UserId)
+
+	exists, err := h.RedisClient.Exists(ctx, key).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	if exists == 0 {
+		err = h.rebuildHomeTimeline(ctx, req.UserId)
+	} else {
+		err = h.RedisClient.Expire(ctx, key, h.Config.Timeline.TTL).Err()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	max := "+inf"
+	if req.Before > 0 {
+		max = "(" + strconv.FormatInt(req.Before, 10)
+	}
+
+	cached, err := h.RedisClient.ZRevRangeByScoreWithScores(ctx, key, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   max,
+		Count: int64(req.Limit),
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]entity.TimelineEntry, 0, len(cached))
+	for _, z := range cached {
+		entries = append(entries, entity.TimelineEntry{
+			TweetId: z.Member.(string),
+			Score:   int64(z.Score),
+		})
+	}
+
+	fanOutOnRead, err := h.RedisClient.SMembers(ctx, worker.FanOutOnReadKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	followed, err := h.UseCase.FollowerRepo.FollowedAmong(ctx, entity.FollowedAmongRequest{
+		UserId:  req.UserId,
+		UserIds: fanOutOnRead,
+	})
+	if err != nil || len(followed) == 0 {
+		return entries, err
+	}
+
+	merged, err := h.UseCase.TweetRepo.TimelineEntries(ctx, entity.TimelineRequest{
+		OwnerIds: followed,
+		Before:   req.Before,
+		Limit:    req.Limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return mergeTimelineEntries(req.Limit, entries, merged), nil
+}
+
+// rebuildHomeTimeline caches the newest tweets of the user and of the followed accounts.
+func (h *Handler) rebuildHomeTimeline(ctx context.Context, userID string) error {
+	entries, err := h.UseCase.TweetRepo.TimelineEntries(ctx, entity.TimelineRequest{
+		UserId: userID,
+		Limit:  int(h.Config.Timeline.MaxSize),
+	})
+	if err != nil || len(entries) == 0 {
+		return err
+	}
+
+	members := make([]redis.Z, 0, len(entries))
+	for _, entry := range entries {
+		members = append(members, redis.Z{
+			Score:  float64(entry.Score),
+			Member: entry.TweetId,
+		})
+	}
+
+	key := worker.HomeTimelineKey(userID)
+	_, err = h.RedisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZAdd(ctx, key, members...)
+		pipe.Expire(ctx, key, h.Config.Timeline.TTL)
+		return nil
+	})
+
+	return err
+}
+
+// mergeTimelineEntries merges timelines newest first without duplicates and cuts the result to limit entries.
+func mergeTimelineEntries(limit int, timelines ...[]entity.TimelineEntry) []entity.TimelineEntry {
+	var (
+		response = []entity.TimelineEntry{}
+		seen     = make(map[string]bool)
+	)
+
+	for _, timeline := range timelines {
+		for _, entry := range timeline {
+			if !seen[entry.TweetId] {
+				seen[entry.TweetId] = true
+				response = append(response, entry)
+			}
+		}
+	}
+
+	sort.SliceStable(response, func(i, j int) bool {
+		return response[i].Score > response[j].Score
+	})
+
+	if len(response) > limit {
+		response = response[:limit]
+	}
+
+	return response
+}
+
+// backfillHomeTimeline adds the recent tweets of a newly followed account to the cached timeline of the follower.
+func (h *Handler) backfillHomeTimeline(ctx context.Context, follow entity.Follower) error {
+	entries, err := h.UseCase.TweetRepo.TimelineEntries(ctx, entity.TimelineRequest{
+		OwnerIds: []string{follow.FollowingId},
+		Limit:    h.Config.Timeline.BackfillSize,
+	})
+	if err != nil || len(entries) == 0 {
+		return err
+	}
+
+	return h.FanOut.Push(ctx, []string{follow.FollowerId}, entries...)
+}
@@ -0,0 +1,283 @@
+package handler
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golanguzb70/udevslabs-twitter/config"
+	"github.com/golanguzb70/udevslabs-twitter/internal/entity"
+	"github.com/golanguzb70/udevslabs-twitter/pkg/etc"
+	"github.com/golanguzb70/udevslabs-twitter/pkg/hash"
+)
+
+const (
+	// _tokenPrefix tells personal access tokens apart from jwt tokens in the Authorization header.
+	_tokenPrefix = "mtp_"
+	// _tokenLastUsedInterval throttles last_used_at writes, a busy bot would otherwise update it on every request.
+	_tokenLastUsedInterval = time.Minute
+)
+
+// _tokenResources are the route groups under /v1 a personal access token can be scoped to.
+// Scopes are "<resource>:read" for GET requests and "<resource>:write" for the rest.
+var _tokenResources = []string{"user", "tweet", "follower", "tag"}
+
+// CreateToken godoc
+// @Router /tokens [post]
+// @Summary Create a personal access token
+// @Description Creates a named token for bots and scripts, pass it as "Authorization: Bearer <token>".
+// @Description Scopes are <resource>:read or <resource>:write, resources are user, tweet, follower and tag.
+// @Description The token is returned only once, expires_at is optional.
+// @Security BearerAuth
+// @Tags tokens
+// @Accept  json
+// @Produce  json
+// @Param body body entity.PersonalAccessTokenCreateRequest true "Token"
+// @Success 200 {object} entity.PersonalAccessToken
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) CreateToken(ctx *gin.Context) {
+	var (
+		body entity.PersonalAccessTokenCreateRequest
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	if ctx.GetHeader("token_id") != "" {
+		h.ReturnError(ctx, config.ErrorForbidden, "Personal access tokens can't create tokens", http.StatusForbidden)
+		return
+	}
+
+	body.Name = strings.TrimSpace(body.Name)
+	if body.Name == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Name is required", http.StatusBadRequest)
+		return
+	}
+
+	if len(body.Scopes) == 0 {
+		h.ReturnError(ctx, config.ErrorBadRequest, "At least one scope is required", http.StatusBadRequest)
+		return
+	}
+
+	for _, scope := range body.Scopes {
+		if !isValidScope(scope) {
+			h.ReturnError(ctx, config.ErrorBadRequest, "Invalid scope: "+scope, http.StatusBadRequest)
+			return
+		}
+	}
+
+	if body.ExpiresAt != "" {
+		expiresAt, err := time.Parse(time.RFC3339, body.ExpiresAt)
+		if err != nil || expiresAt.Before(time.Now()) {
+			h.ReturnError(ctx, config.ErrorBadRequest, "expires_at must be a future RFC3339 time", http.StatusBadRequest)
+			return
+		}
+		body.ExpiresAt = expiresAt.UTC().Format(time.RFC3339)
+	}
+
+	secret, err := etc.GenerateToken(32)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorInternalServer, "Error generating token", http.StatusInternalServerError)
+		return
+	}
+
+	token := _tokenPrefix + secret
+
+	slices.Sort(body.Scopes)
+
+	pat, err := h.UseCase.PersonalAccessTokenRepo.Create(ctx, entity.PersonalAccessToken{
+		UserID:    ctx.GetHeader("sub"),
+		Name:      body.Name,
+		TokenHash: hash.HashToken(token),
+		Scopes:    slices.Compact(body.Scopes),
+		ExpiresAt: body.ExpiresAt,
+	})
+	if h.HandleDbError(ctx, err, "Error creating token") {
+		return
+	}
+
+	pat.Token = token
+
+	ctx.JSON(200, pat)
+}
+
+// GetTokens godoc
+// @Router /tokens/list [get]
+// @Summary Get a list of personal access tokens
+// @Description Users get their own tokens, admins can filter by user_id
+// @Security BearerAuth
+// @Tags tokens
+// @Accept  json
+// @Produce  json
+// @Param page query number true "page"
+// @Param limit query number true "limit"
+// @Param user_id query string false "user_id"
+// @Success 200 {object} entity.PersonalAccessTokenList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetTokens(ctx *gin.Context) {
+	var (
+		req entity.GetListFilter
+	)
+
+	page := ctx.DefaultQuery("page", "1")
+	limit := ctx.DefaultQuery("limit", "10")
+	userId := ctx.DefaultQuery("user_id", "")
+
+	if ctx.GetHeader("user_type") == "user" || userId == "" {
+		userId = ctx.GetHeader("sub")
+	}
+
+	req.Page, _ = strconv.Atoi(page)
+	req.Limit, _ = strconv.Atoi(limit)
+	req.Filters = append(req.Filters,
+		entity.Filter{
+			Column: "user_id",
+			Type:   "eq",
+			Value:  userId,
+		},
+	)
+
+	req.OrderBy = append(req.OrderBy, entity.OrderBy{
+		Column: "created_at",
+		Order:  "desc",
+	})
+
+	tokens, err := h.UseCase.PersonalAccessTokenRepo.GetList(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting tokens") {
+		return
+	}
+
+	ctx.JSON(200, tokens)
+}
+
+// DeleteToken godoc
+// @Router /tokens/{id} [delete]
+// @Summary Revoke a personal access token
+// @Description Revoke a personal access token, it stops working immediately
+// @Security BearerAuth
+// @Tags tokens
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Token ID"
+// @Success 200 {object} entity.SuccessResponse
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+func (h *Handler) DeleteToken(ctx *gin.Context) {
+	var (
+		req entity.Id
+	)
+
+	req.ID = ctx.Param("id")
+
+	err := h.UseCase.PersonalAccessTokenRepo.Delete(ctx, req)
+	if h.HandleDbError(ctx, err, "Error deleting token") {
+		return
+	}
+
+	ctx.JSON(200, entity.SuccessResponse{
+		Message: "Token revoked successfully",
+	})
+}
+
+// authenticateToken validates a personal access token and sets the same headers a jwt would,
+// plus token_id. It aborts the request and returns false if the token is invalid or its
+// scopes don't cover the route.
+func (h *Handler) authenticateToken(c *gin.Context, token string) (string, bool) {
+	pat, err := h.UseCase.PersonalAccessTokenRepo.GetSingle(c, entity.PersonalAccessTokenSingleRequest{
+		TokenHash: hash.HashToken(token),
+	})
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, entity.ErrorResponse{
+			Message: "Invalid personal access token",
+			Code:    config.ErrorInvalidToken,
+		})
+		return "", false
+	}
+
+	if isExpired(pat.ExpiresAt) {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, entity.ErrorResponse{
+			Message: "Personal access token is expired",
+			Code:    config.ErrorSessionExpired,
+		})
+		return "", false
+	}
+
+	scope, ok := requiredScope(c.FullPath(), c.Request.Method)
+	if !ok || !slices.Contains(pat.Scopes, scope) {
+		c.AbortWithStatusJSON(http.StatusForbidden, entity.ErrorResponse{
+			Message: "Personal access token doesn't have the required scope " + scope,
+			Code:    config.ErrorInsufficientScope,
+		})
+		return "", false
+	}
+
+	user, err := h.UseCase.UserRepo.GetSingle(c, entity.UserSingleRequest{ID: pat.UserID})
+	if err != nil || user.Status == "deactivated" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, entity.ErrorResponse{
+			Message: "Invalid personal access token",
+			Code:    config.ErrorInvalidToken,
+		})
+		return "", false
+	}
+
+	h.touchToken(c, pat)
+
+	c.Request.Header.Set("sub", user.ID)
+	c.Request.Header.Set("user_role", user.UserRole)
+	c.Request.Header.Set("user_type", user.UserType)
+	c.Request.Header.Set("token_id", pat.ID)
+
+	return user.UserRole, true
+}
+
+// touchToken records when and from where the token was last used, at most once per _tokenLastUsedInterval.
+func (h *Handler) touchToken(c *gin.Context, pat entity.PersonalAccessToken) {
+	if lastUsedAt, err := time.Parse(time.RFC3339, pat.LastUsedAt); err == nil && time.Since(lastUsedAt) < _tokenLastUsedInterval {
+		return
+	}
+
+	_, err := h.UseCase.PersonalAccessTokenRepo.UpdateField(c, entity.UpdateFieldRequest{
+		Filter: []entity.Filter{
+			{Column: "id", Type: "eq", Value: pat.ID},
+		},
+		Items: []entity.UpdateFieldItem{
+			{Column: "last_used_at", Value: time.Now().UTC()},
+			{Column: "last_used_ip", Value: c.ClientIP()},
+		},
+	})
+	if err != nil {
+		h.Logger.Error(err, "Error updating token last used time")
+	}
+}
+
+// requiredScope returns the scope a route needs, e.g. GET /v1/tweet/:id needs tweet:read.
+// Routes outside _tokenResources can't be used with personal access tokens.
+func requiredScope(path, method string) (string, bool) {
+	resource, _, _ := strings.Cut(strings.TrimPrefix(path, "/v1/"), "/")
+	if !slices.Contains(_tokenResources, resource) {
+		return "", false
+	}
+
+	if method == http.MethodGet {
+		return resource + ":read", true
+	}
+
+	return resource + ":write", true
+}
+
+func isValidScope(scope string) bool {
+	resource, access, _ := strings.Cut(scope, ":")
+
+	return slices.Contains(_tokenResources, resource) && (access == "read" || access == "write")
+}
+
+func isPersonalAccessToken(token string) bool {
+	return strings.HasPrefix(token, _tokenPrefix)
+}
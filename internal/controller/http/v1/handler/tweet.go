@@ -2,11 +2,14 @@ package handler
 
 import (
 	"net/http"
+	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golanguzb70/udevslabs-twitter/config"
 	"github.com/golanguzb70/udevslabs-twitter/internal/entity"
+	"github.com/jackc/pgx/v4"
 )
 
 // CreateTweet godoc
@@ -17,6 +20,9 @@ import (
 // @Tags tweet
 // @Accept  json
 // @Produce  json
+// @Description Set reply_to to the id of a published tweet to reply to it,
+// @Description and quote_of_id to quote one, the quoted tweet is returned as original in reads.
+// @Description Use status scheduled with a future publish_at to publish it later.
 // @Param tweet body entity.Tweet true "Tweet object"
 // @Success 201 {object} entity.Tweet
 // @Failure 400 {object} entity.ErrorResponse
@@ -32,6 +38,40 @@ func (h *Handler) CreateTweet(ctx *gin.Context) {
 	}
 
 	body.Owner.ID = ctx.GetHeader("sub")
+	body.ParentId, body.ConversationId, body.RetweetOfId = "", "", ""
+
+	if body.Status == "scheduled" && !futurePublishAt(body.PublishAt) {
+		h.ReturnError(ctx, config.ErrorBadRequest, "publish_at must be an RFC3339 time in the future", http.StatusBadRequest)
+		return
+	}
+
+	if body.ReplyTo != "" {
+		parent, err := h.UseCase.TweetRepo.GetSingle(ctx, entity.Id{ID: body.ReplyTo})
+		if h.HandleDbError(ctx, err, "Error getting the replied tweet") {
+			return
+		}
+
+		if parent.Status != "published" {
+			h.ReturnError(ctx, config.ErrorBadRequest, "Only published tweets can be replied to", http.StatusBadRequest)
+			return
+		}
+
+		body.ParentId, body.ConversationId = parent.Id, parent.ConversationId
+	}
+
+	if body.QuoteOfId != "" {
+		if strings.TrimSpace(body.Content) == "" {
+			h.ReturnError(ctx, config.ErrorBadRequest, "A quote tweet needs content, use retweet to repost as is", http.StatusBadRequest)
+			return
+		}
+
+		quoted, ok := h.repostTarget(ctx, body.QuoteOfId)
+		if !ok {
+			return
+		}
+
+		body.QuoteOfId = quoted.Id
+	}
 
 	tweet, err := h.UseCase.TweetRepo.Create(ctx, body)
 	if h.HandleDbError(ctx, err, "Error creating tweet") {
@@ -48,6 +88,10 @@ func (h *Handler) CreateTweet(ctx *gin.Context) {
 
 	// create tags for the tweet.
 
+	if tweet.Status == "published" {
+		h.FanOut.Publish(tweet)
+	}
+
 	ctx.JSON(201, tweet)
 }
 
@@ -98,19 +142,34 @@ func (h *Handler) GetTweet(ctx *gin.Context) {
 		return
 	}
 
+	if !h.canSeeUser(ctx, tweet.Owner) || !h.canSeeTweet(ctx, tweet) {
+		h.HandleDbError(ctx, pgx.ErrNoRows, "Error getting tweet")
+		return
+	}
+
+	tweets := []entity.Tweet{tweet}
+	if !h.setViewerState(ctx, tweets) {
+		return
+	}
+	tweet = tweets[0]
+
 	ctx.JSON(200, tweet)
 }
 
 // GetTweets godoc
 // @Router /tweet/list [get]
 // @Summary Get a list of tweets
-// @Description Get a list of tweets
+// @Description Get a list of tweets. A retweet is listed with the reposting user as owner and the retweeted tweet as original.
+// @Description Users only get published tweets, scheduled ones are listed by /tweet/scheduled/list.
+// @Description Pass next_cursor or prev_cursor of a page as cursor to get the neighbouring page, page is ignored then.
 // @Security BearerAuth
 // @Tags tweet
 // @Accept  json
 // @Produce  json
-// @Param page query number true "page"
+// @Param page query number false "page"
 // @Param limit query number true "limit"
+// @Param cursor query string false "cursor"
+// @Param skip_count query bool false "skip_count"
 // @Param search query string false "search"
 // @Success 200 {object} entity.TweetList
 // @Failure 400 {object} entity.ErrorResponse
@@ -125,6 +184,8 @@ func (h *Handler) GetTweets(ctx *gin.Context) {
 
 	req.Page, _ = strconv.Atoi(page)
 	req.Limit, _ = strconv.Atoi(limit)
+	req.Cursor = ctx.Query("cursor")
+	req.SkipCount = ctx.Query("skip_count") == "true"
 	req.Filters = append(req.Filters,
 		entity.Filter{
 			Column: "content",
@@ -133,6 +194,14 @@ func (h *Handler) GetTweets(ctx *gin.Context) {
 		},
 	)
 
+	if ctx.GetHeader("user_type") == "user" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "tweet.status",
+			Type:   "eq",
+			Value:  "published",
+		})
+	}
+
 	req.OrderBy = append(req.OrderBy, entity.OrderBy{
 		Column: "created_at",
 		Order:  "desc",
@@ -143,13 +212,18 @@ func (h *Handler) GetTweets(ctx *gin.Context) {
 		return
 	}
 
+	if !h.setViewerState(ctx, tweets.Items) {
+		return
+	}
+
 	ctx.JSON(200, tweets)
 }
 
 // UpdateTweet godoc
 // @Router /tweet [put]
 // @Summary Update a tweet
-// @Description Update a tweet
+// @Description Update a tweet. Editing the content or attachments of a published tweet keeps the prior version
+// @Description in its history, published tweets can only be edited within the configured edit window.
 // @Security BearerAuth
 // @Tags tweet
 // @Accept  json
@@ -157,6 +231,7 @@ func (h *Handler) GetTweets(ctx *gin.Context) {
 // @Param tweet body entity.Tweet true "Tweet object"
 // @Success 200 {object} entity.Tweet
 // @Failure 400 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
 func (h *Handler) UpdateTweet(ctx *gin.Context) {
 	var (
 		body entity.Tweet
@@ -168,11 +243,21 @@ func (h *Handler) UpdateTweet(ctx *gin.Context) {
 		return
 	}
 
-	if body.Owner.ID != ctx.GetHeader("sub") {
-		h.ReturnError(ctx, config.ErrorForbidden, "You have no access to the tweet", http.StatusForbidden)
-		return
+	// a scheduled tweet can be edited as is, the publish time only changes through the schedule endpoint
+	if body.Status == "scheduled" {
+		current, err := h.UseCase.TweetRepo.GetSingle(ctx, entity.Id{ID: body.Id})
+		if h.HandleDbError(ctx, err, "Error getting tweet") {
+			return
+		}
+
+		if current.Status != "scheduled" {
+			h.ReturnError(ctx, config.ErrorBadRequest, "Use the schedule endpoint to schedule a tweet", http.StatusBadRequest)
+			return
+		}
 	}
 
+	body.EditorId = ctx.GetHeader("sub")
+
 	tweet, err := h.UseCase.TweetRepo.Update(ctx, body)
 	if h.HandleDbError(ctx, err, "Error updating tweet") {
 		return
@@ -186,9 +271,72 @@ func (h *Handler) UpdateTweet(ctx *gin.Context) {
 		return
 	}
 
+	// publishing a draft puts it on the timelines, edits of published tweets don't move them
+	if tweet.Published {
+		h.FanOut.Publish(tweet)
+	}
+
 	ctx.JSON(200, tweet)
 }
 
+// GetTweetHistory godoc
+// @Router /tweet/{id}/history [get]
+// @Summary Get the edit history of a tweet
+// @Description Prior versions of the tweet, the latest first. Each revision was replaced by editor_id at created_at.
+// @Security BearerAuth
+// @Tags tweet
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Tweet ID"
+// @Param page query number true "page"
+// @Param limit query number true "limit"
+// @Success 200 {object} entity.TweetRevisionList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetTweetHistory(ctx *gin.Context) {
+	var (
+		req entity.GetListFilter
+	)
+
+	tweet, err := h.UseCase.TweetRepo.GetSingle(ctx, entity.Id{ID: ctx.Param("id")})
+	if h.HandleDbError(ctx, err, "Error getting tweet") {
+		return
+	}
+
+	tweet.Owner, err = h.UseCase.UserRepo.GetSingle(ctx, entity.UserSingleRequest{ID: tweet.Owner.ID})
+	if h.HandleDbError(ctx, err, "Error getting tweet owner") {
+		return
+	}
+
+	// the history is visible to whoever can see the tweet
+	if !h.canSeeUser(ctx, tweet.Owner) || !h.canSeeTweet(ctx, tweet) {
+		h.HandleDbError(ctx, pgx.ErrNoRows, "Error getting tweet")
+		return
+	}
+
+	page := ctx.DefaultQuery("page", "1")
+	limit := ctx.DefaultQuery("limit", "10")
+
+	req.Page, _ = strconv.Atoi(page)
+	req.Limit, _ = strconv.Atoi(limit)
+	req.Filters = append(req.Filters, entity.Filter{
+		Column: "tweet_id",
+		Type:   "eq",
+		Value:  tweet.Id,
+	})
+
+	req.OrderBy = append(req.OrderBy, entity.OrderBy{
+		Column: "revision",
+		Order:  "desc",
+	})
+
+	revisions, err := h.UseCase.TweetRevisionRepo.GetList(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting tweet history") {
+		return
+	}
+
+	ctx.JSON(200, revisions)
+}
+
 // DeleteTweet godoc
 // @Router /tweet/{id} [delete]
 // @Summary Delete a tweet
@@ -200,6 +348,7 @@ func (h *Handler) UpdateTweet(ctx *gin.Context) {
 // @Param id path string true "Tweet ID"
 // @Success 200 {object} entity.SuccessResponse
 // @Failure 400 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
 func (h *Handler) DeleteTweet(ctx *gin.Context) {
 	var (
 		req entity.Id
@@ -207,22 +356,286 @@ func (h *Handler) DeleteTweet(ctx *gin.Context) {
 
 	req.ID = ctx.Param("id")
 
-	tweet, err := h.UseCase.TweetRepo.GetSingle(ctx, req)
+	err := h.UseCase.TweetRepo.Delete(ctx, req)
+	if h.HandleDbError(ctx, err, "Error deleting tweet") {
+		return
+	}
+
+	ctx.JSON(200, entity.SuccessResponse{
+		Message: "Tweet deleted successfully",
+	})
+}
+
+// GetTweetReplies godoc
+// @Router /tweet/{id}/replies [get]
+// @Summary Get the direct replies of a tweet
+// @Description Published replies, oldest first
+// @Security BearerAuth
+// @Tags tweet
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Tweet ID"
+// @Param page query number true "page"
+// @Param limit query number true "limit"
+// @Success 200 {object} entity.TweetList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetTweetReplies(ctx *gin.Context) {
+	var (
+		req entity.GetListFilter
+	)
+
+	page := ctx.DefaultQuery("page", "1")
+	limit := ctx.DefaultQuery("limit", "10")
+
+	req.Page, _ = strconv.Atoi(page)
+	req.Limit, _ = strconv.Atoi(limit)
+	req.Filters = append(req.Filters,
+		entity.Filter{
+			Column: "parent_id",
+			Type:   "eq",
+			Value:  ctx.Param("id"),
+		},
+		entity.Filter{
+			Column: "status",
+			Type:   "eq",
+			Value:  "published",
+		},
+	)
+
+	req.OrderBy = append(req.OrderBy, entity.OrderBy{
+		Column: "created_at",
+		Order:  "asc",
+	})
+
+	tweets, err := h.UseCase.TweetRepo.GetList(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting replies") {
+		return
+	}
+
+	if !h.setViewerState(ctx, tweets.Items) {
+		return
+	}
+
+	ctx.JSON(200, tweets)
+}
+
+// GetConversation godoc
+// @Router /tweet/{id}/conversation [get]
+// @Summary Get the conversation of a tweet
+// @Description Returns the whole thread the tweet belongs to as a tree starting at its root.
+// @Description Replies of a tweet are ordered oldest first, except that the root author's replies come first
+// @Description so a thread written by the author reads top to bottom. Replies of a deleted tweet are
+// @Description attached to its closest remaining ancestor, the root itself may be empty if it was deleted.
+// @Security BearerAuth
+// @Tags tweet
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Tweet ID"
+// @Success 200 {object} entity.Conversation
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetConversation(ctx *gin.Context) {
+	tweet, err := h.UseCase.TweetRepo.GetSingle(ctx, entity.Id{ID: ctx.Param("id")})
 	if h.HandleDbError(ctx, err, "Error getting tweet") {
 		return
 	}
 
-	if tweet.Owner.ID != ctx.GetHeader("sub") {
-		h.ReturnError(ctx, config.ErrorForbidden, "You have no access to the tweet", http.StatusForbidden)
+	tweets, err := h.UseCase.TweetRepo.GetList(ctx, entity.GetListFilter{
+		Filters: []entity.Filter{
+			{Column: "conversation_id", Type: "eq", Value: tweet.ConversationId},
+			{Column: "status", Type: "eq", Value: "published"},
+		},
+		OrderBy: []entity.OrderBy{{Column: "created_at", Order: "asc"}},
+		Page:    1,
+		Limit:   h.Config.Tweet.ConversationMaxSize,
+	})
+	if h.HandleDbError(ctx, err, "Error getting conversation") {
+		return
+	}
+
+	if !h.setViewerState(ctx, tweets.Items) {
 		return
 	}
 
-	err = h.UseCase.TweetRepo.Delete(ctx, req)
-	if h.HandleDbError(ctx, err, "Error deleting tweet") {
+	ctx.JSON(200, entity.Conversation{
+		Root:  buildConversation(tweet.ConversationId, tweets.Items),
+		Count: int64(len(tweets.Items)),
+	})
+}
+
+// buildConversation nests the tweets of a conversation under their parents, tweets
+// whose parent is missing (deleted, a draft or hidden) hang off the root.
+func buildConversation(rootID string, tweets []entity.Tweet) entity.Tweet {
+	var (
+		root     = entity.Tweet{Id: rootID, ConversationId: rootID}
+		loaded   = make(map[string]bool, len(tweets))
+		children = make(map[string][]entity.Tweet)
+	)
+
+	for _, tweet := range tweets {
+		loaded[tweet.Id] = true
+		if tweet.Id == rootID {
+			root = tweet
+		}
+	}
+
+	for _, tweet := range tweets {
+		if tweet.Id == rootID {
+			continue
+		}
+
+		parentID := tweet.ParentId
+		if !loaded[parentID] {
+			parentID = rootID
+		}
+		children[parentID] = append(children[parentID], tweet)
+	}
+
+	var attach func(tweet *entity.Tweet)
+	attach = func(tweet *entity.Tweet) {
+		replies := children[tweet.Id]
+
+		// tweets are loaded oldest first, the stable sort keeps that order within both groups
+		sort.SliceStable(replies, func(i, j int) bool {
+			return replies[i].Owner.ID == root.Owner.ID && replies[j].Owner.ID != root.Owner.ID
+		})
+
+		for i := range replies {
+			attach(&replies[i])
+		}
+
+		tweet.Replies = replies
+	}
+	attach(&root)
+
+	return root
+}
+
+// Retweet godoc
+// @Router /tweet/{id}/retweet [post]
+// @Summary Retweet a tweet
+// @Description Reposts the tweet, retweeting it again returns the existing retweet. Retweeting a retweet reposts its original.
+// @Security BearerAuth
+// @Tags tweet
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Tweet ID"
+// @Success 200 {object} entity.Tweet
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) Retweet(ctx *gin.Context) {
+	original, ok := h.repostTarget(ctx, ctx.Param("id"))
+	if !ok {
+		return
+	}
+
+	tweet, err := h.UseCase.TweetRepo.Retweet(ctx, entity.RetweetRequest{
+		UserId:  ctx.GetHeader("sub"),
+		TweetId: original.Id,
+	})
+	if h.HandleDbError(ctx, err, "Error retweeting") {
+		return
+	}
+
+	// a repeated retweet is already on the timelines
+	if tweet.Published {
+		h.FanOut.Publish(tweet)
+	}
+
+	ctx.JSON(200, tweet)
+}
+
+// Unretweet godoc
+// @Router /tweet/{id}/retweet [delete]
+// @Summary Undo a retweet
+// @Description Removes the retweet of the tweet, it succeeds if there is none
+// @Security BearerAuth
+// @Tags tweet
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Tweet ID"
+// @Success 200 {object} entity.SuccessResponse
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) Unretweet(ctx *gin.Context) {
+	tweetID := ctx.Param("id")
+
+	// the id may be the retweet itself
+	tweet, err := h.UseCase.TweetRepo.GetSingle(ctx, entity.Id{ID: tweetID})
+	if err == nil && tweet.RetweetOfId != "" {
+		tweetID = tweet.RetweetOfId
+	} else if err != nil && err != pgx.ErrNoRows {
+		h.HandleDbError(ctx, err, "Error getting tweet")
+		return
+	}
+
+	err = h.UseCase.TweetRepo.Unretweet(ctx, entity.RetweetRequest{
+		UserId:  ctx.GetHeader("sub"),
+		TweetId: tweetID,
+	})
+	if h.HandleDbError(ctx, err, "Error undoing retweet") {
 		return
 	}
 
 	ctx.JSON(200, entity.SuccessResponse{
-		Message: "Tweet deleted successfully",
+		Message: "Retweet removed successfully",
 	})
 }
+
+// canSeeTweet reports whether the requester may see the tweet, drafts and scheduled tweets
+// are visible only to their owner and admins.
+func (h *Handler) canSeeTweet(ctx *gin.Context, tweet entity.Tweet) bool {
+	return tweet.Status == "published" || ctx.GetHeader("user_type") != "user" || ctx.GetHeader("sub") == tweet.Owner.ID
+}
+
+// repostTarget loads the tweet to retweet or quote, reposts of a retweet point at its original.
+func (h *Handler) repostTarget(ctx *gin.Context, id string) (entity.Tweet, bool) {
+	tweet, err := h.UseCase.TweetRepo.GetSingle(ctx, entity.Id{ID: id})
+	if h.HandleDbError(ctx, err, "Error getting tweet") {
+		return entity.Tweet{}, false
+	}
+
+	if tweet.RetweetOfId != "" {
+		tweet, err = h.UseCase.TweetRepo.GetSingle(ctx, entity.Id{ID: tweet.RetweetOfId})
+		if h.HandleDbError(ctx, err, "Error getting tweet") {
+			return entity.Tweet{}, false
+		}
+	}
+
+	if tweet.Status != "published" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Only published tweets can be reposted", http.StatusBadRequest)
+		return entity.Tweet{}, false
+	}
+
+	return tweet, true
+}
+
+// setViewerState fills liked_by_me and bookmarked_by_me of the tweets and their originals
+// for the requesting user, with a query per flag.
+func (h *Handler) setViewerState(ctx *gin.Context, tweets []entity.Tweet) bool {
+	req := entity.UserTweetsRequest{UserId: ctx.GetHeader("sub")}
+	for _, tweet := range tweets {
+		req.TweetIds = append(req.TweetIds, tweet.Id)
+		if tweet.Original != nil {
+			req.TweetIds = append(req.TweetIds, tweet.Original.Id)
+		}
+	}
+
+	liked, err := h.UseCase.TweetLikeRepo.LikedTweets(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting likes") {
+		return false
+	}
+
+	bookmarked, err := h.UseCase.BookmarkRepo.BookmarkedTweets(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting bookmarks") {
+		return false
+	}
+
+	for i := range tweets {
+		tweets[i].LikedByMe = liked[tweets[i].Id]
+		tweets[i].BookmarkedByMe = bookmarked[tweets[i].Id]
+		if original := tweets[i].Original; original != nil {
+			original.LikedByMe = liked[original.Id]
+			original.BookmarkedByMe = bookmarked[original.Id]
+		}
+	}
+
+	return true
+}
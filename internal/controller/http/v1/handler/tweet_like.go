@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golanguzb70/udevslabs-twitter/config"
+	"github.com/golanguzb70/udevslabs-twitter/internal/entity"
+)
+
+// LikeTweet godoc
+// @Router /tweet/{id}/like [post]
+// @Summary Like a tweet
+// @Description Likes the tweet, liking it again changes nothing
+// @Security BearerAuth
+// @Tags tweet
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Tweet ID"
+// @Success 200 {object} entity.TweetLike
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) LikeTweet(ctx *gin.Context) {
+	tweet, err := h.UseCase.TweetRepo.GetSingle(ctx, entity.Id{ID: ctx.Param("id")})
+	if h.HandleDbError(ctx, err, "Error getting tweet") {
+		return
+	}
+
+	if tweet.Status != "published" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Only published tweets can be liked", http.StatusBadRequest)
+		return
+	}
+
+	like, err := h.UseCase.TweetLikeRepo.Like(ctx, entity.TweetLike{
+		TweetId: tweet.Id,
+		UserId:  ctx.GetHeader("sub"),
+	})
+	if h.HandleDbError(ctx, err, "Error liking tweet") {
+		return
+	}
+
+	ctx.JSON(200, like)
+}
+
+// UnlikeTweet godoc
+// @Router /tweet/{id}/like [delete]
+// @Summary Unlike a tweet
+// @Description Removes the like of the tweet, it succeeds if there is none
+// @Security BearerAuth
+// @Tags tweet
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Tweet ID"
+// @Success 200 {object} entity.TweetLike
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) UnlikeTweet(ctx *gin.Context) {
+	like, err := h.UseCase.TweetLikeRepo.Unlike(ctx, entity.TweetLike{
+		TweetId: ctx.Param("id"),
+		UserId:  ctx.GetHeader("sub"),
+	})
+	if h.HandleDbError(ctx, err, "Error unliking tweet") {
+		return
+	}
+
+	ctx.JSON(200, like)
+}
+
+// GetTweetLikes godoc
+// @Router /tweet/{id}/likes [get]
+// @Summary Get the users who liked a tweet
+// @Description Latest likes first
+// @Security BearerAuth
+// @Tags tweet
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Tweet ID"
+// @Param page query number true "page"
+// @Param limit query number true "limit"
+// @Success 200 {object} entity.UserList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetTweetLikes(ctx *gin.Context) {
+	var (
+		req entity.GetListFilter
+	)
+
+	page := ctx.DefaultQuery("page", "1")
+	limit := ctx.DefaultQuery("limit", "10")
+
+	req.Page, _ = strconv.Atoi(page)
+	req.Limit, _ = strconv.Atoi(limit)
+	req.Filters = append(req.Filters, entity.Filter{
+		Column: "l.tweet_id",
+		Type:   "eq",
+		Value:  ctx.Param("id"),
+	})
+
+	req.OrderBy = append(req.OrderBy, entity.OrderBy{
+		Column: "l.created_at",
+		Order:  "desc",
+	})
+
+	users, err := h.UseCase.TweetLikeRepo.GetList(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting likes") {
+		return
+	}
+
+	ctx.JSON(200, users)
+}
@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golanguzb70/udevslabs-twitter/config"
+	"github.com/golanguzb70/udevslabs-twitter/internal/entity"
+)
+
+// GetScheduledTweets godoc
+// @Router /tweet/scheduled/list [get]
+// @Summary Get the scheduled tweets
+// @Description Scheduled tweets of the caller, the next to be published first. Admins can pass owner_id to see those of a user.
+// @Description Pass next_cursor or prev_cursor of a page as cursor to get the neighbouring page, page is ignored then.
+// @Security BearerAuth
+// @Tags tweet
+// @Accept  json
+// @Produce  json
+// @Param page query number false "page"
+// @Param limit query number true "limit"
+// @Param cursor query string false "cursor"
+// @Param skip_count query bool false "skip_count"
+// @Param owner_id query string false "owner_id"
+// @Success 200 {object} entity.TweetList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetScheduledTweets(ctx *gin.Context) {
+	var (
+		req entity.GetListFilter
+	)
+
+	page := ctx.DefaultQuery("page", "1")
+	limit := ctx.DefaultQuery("limit", "10")
+	ownerID := ctx.DefaultQuery("owner_id", ctx.GetHeader("sub"))
+
+	if ctx.GetHeader("user_type") == "user" {
+		ownerID = ctx.GetHeader("sub")
+	}
+
+	req.Page, _ = strconv.Atoi(page)
+	req.Limit, _ = strconv.Atoi(limit)
+	req.Cursor = ctx.Query("cursor")
+	req.SkipCount = ctx.Query("skip_count") == "true"
+	req.Filters = append(req.Filters, entity.Filter{
+		Column: "tweet.owner_id",
+		Type:   "eq",
+		Value:  ownerID,
+	})
+
+	req.OrderBy = append(req.OrderBy, entity.OrderBy{
+		Column: "tweet.publish_at",
+		Order:  "asc",
+	})
+
+	tweets, err := h.UseCase.TweetRepo.GetScheduled(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting scheduled tweets") {
+		return
+	}
+
+	ctx.JSON(200, tweets)
+}
+
+// ScheduleTweet godoc
+// @Router /tweet/{id}/schedule [put]
+// @Summary Schedule a tweet
+// @Description Schedules a draft or reschedules a scheduled tweet, it is published at publish_at
+// @Security BearerAuth
+// @Tags tweet
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Tweet ID"
+// @Param schedule body entity.ScheduleTweetRequest true "Publish time"
+// @Success 200 {object} entity.Tweet
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+func (h *Handler) ScheduleTweet(ctx *gin.Context) {
+	var (
+		body entity.ScheduleTweetRequest
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	if !futurePublishAt(body.PublishAt) {
+		h.ReturnError(ctx, config.ErrorBadRequest, "publish_at must be an RFC3339 time in the future", http.StatusBadRequest)
+		return
+	}
+
+	body.Id = ctx.Param("id")
+
+	tweet, err := h.UseCase.TweetRepo.Schedule(ctx, body)
+	if h.HandleDbError(ctx, err, "Error scheduling tweet") {
+		return
+	}
+
+	ctx.JSON(200, tweet)
+}
+
+// CancelScheduledTweet godoc
+// @Router /tweet/{id}/schedule [delete]
+// @Summary Cancel a scheduled tweet
+// @Description The tweet is kept as a draft
+// @Security BearerAuth
+// @Tags tweet
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Tweet ID"
+// @Success 200 {object} entity.Tweet
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+func (h *Handler) CancelScheduledTweet(ctx *gin.Context) {
+	tweet, err := h.UseCase.TweetRepo.Unschedule(ctx, entity.Id{ID: ctx.Param("id")})
+	if h.HandleDbError(ctx, err, "Error cancelling scheduled tweet") {
+		return
+	}
+
+	ctx.JSON(200, tweet)
+}
+
+// futurePublishAt reports whether value is an RFC3339 time in the future.
+func futurePublishAt(value string) bool {
+	publishAt, err := time.Parse(time.RFC3339, value)
+	return err == nil && publishAt.After(time.Now())
+}
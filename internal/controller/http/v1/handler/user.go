@@ -1,12 +1,15 @@
 package handler
 
 import (
+	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golanguzb70/udevslabs-twitter/config"
 	"github.com/golanguzb70/udevslabs-twitter/internal/entity"
+	"github.com/golanguzb70/udevslabs-twitter/internal/worker"
 	"github.com/golanguzb70/udevslabs-twitter/pkg/hash"
+	"github.com/jackc/pgx/v4"
 )
 
 // CreateUser godoc
@@ -68,6 +71,11 @@ func (h *Handler) GetUser(ctx *gin.Context) {
 		return
 	}
 
+	if !h.canSeeUser(ctx, user) {
+		h.HandleDbError(ctx, pgx.ErrNoRows, "Error getting user")
+		return
+	}
+
 	user.Password = ""
 
 	ctx.JSON(200, user)
@@ -115,6 +123,14 @@ func (h *Handler) GetUsers(ctx *gin.Context) {
 		},
 	)
 
+	if ctx.GetHeader("user_type") == "user" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "status",
+			Type:   "neq",
+			Value:  "deactivated",
+		})
+	}
+
 	req.OrderBy = append(req.OrderBy, entity.OrderBy{
 		Column: "created_at",
 		Order:  "desc",
@@ -139,6 +155,7 @@ func (h *Handler) GetUsers(ctx *gin.Context) {
 // @Param user body entity.User true "User object"
 // @Success 200 {object} entity.User
 // @Failure 400 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
 func (h *Handler) UpdateUser(ctx *gin.Context) {
 	var (
 		body entity.User
@@ -152,6 +169,18 @@ func (h *Handler) UpdateUser(ctx *gin.Context) {
 
 	if ctx.GetHeader("user_type") == "user" {
 		body.ID = ctx.GetHeader("sub")
+
+		// users change the password and email through /account, which verify them
+		current, err := h.UseCase.UserRepo.GetSingle(ctx, entity.UserSingleRequest{ID: body.ID})
+		if h.HandleDbError(ctx, err, "Error getting user") {
+			return
+		}
+
+		body.Email = current.Email
+		body.Password = ""
+		// and cannot grant themselves another role or status
+		body.UserRole = current.UserRole
+		body.Status = current.Status
 	}
 
 	if body.Password != "" {
@@ -173,7 +202,8 @@ func (h *Handler) UpdateUser(ctx *gin.Context) {
 // DeleteUser godoc
 // @Router /user/{id} [delete]
 // @Summary Delete a user
-// @Description Delete a user
+// @Description Admins delete the user right away. Users deactivate their own account,
+// @Description it is deleted after the grace period unless they log in again, see /account/deactivate.
 // @Security BearerAuth
 // @Tags user
 // @Accept  json
@@ -181,6 +211,8 @@ func (h *Handler) UpdateUser(ctx *gin.Context) {
 // @Param id path string true "User ID"
 // @Success 200 {object} entity.SuccessResponse
 // @Failure 400 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
 func (h *Handler) DeleteUser(ctx *gin.Context) {
 	var (
 		req entity.Id
@@ -189,15 +221,35 @@ func (h *Handler) DeleteUser(ctx *gin.Context) {
 	req.ID = ctx.Param("id")
 
 	if ctx.GetHeader("user_type") == "user" {
-		req.ID = ctx.GetHeader("sub")
+		user, err := h.UseCase.UserRepo.GetSingle(ctx, entity.UserSingleRequest{ID: ctx.GetHeader("sub")})
+		if h.HandleDbError(ctx, err, "Error getting user") {
+			return
+		}
+
+		h.deactivateAccount(ctx, user)
+		return
 	}
 
-	err := h.UseCase.UserRepo.Delete(ctx, req)
+	result, err := h.UseCase.UserRepo.Purge(ctx, entity.UserPurgeRequest{ID: req.ID})
 	if h.HandleDbError(ctx, err, "Error deleting user") {
 		return
 	}
 
+	if len(result.UserIDs) == 0 {
+		h.ReturnError(ctx, config.ErrorNotFound, "User not found", http.StatusNotFound)
+		return
+	}
+
+	h.UseCase.SessionRepo.Invalidate(ctx, result.SessionIDs)
+	worker.RemoveAttachmentFiles(h.Config, h.Logger, result.FilePaths)
+
 	ctx.JSON(200, entity.SuccessResponse{
 		Message: "User deleted successfully",
 	})
 }
+
+// canSeeUser reports whether the requester may see the user, deactivated accounts are
+// visible only to themselves and admins.
+func (h *Handler) canSeeUser(ctx *gin.Context, user entity.User) bool {
+	return user.Status != "deactivated" || ctx.GetHeader("user_type") != "user" || ctx.GetHeader("sub") == user.ID
+}
@@ -16,7 +16,10 @@ import (
 	_ "github.com/golanguzb70/udevslabs-twitter/docs"
 	"github.com/golanguzb70/udevslabs-twitter/internal/controller/http/v1/handler"
 	"github.com/golanguzb70/udevslabs-twitter/internal/usecase"
+	"github.com/golanguzb70/udevslabs-twitter/internal/worker"
+	"github.com/golanguzb70/udevslabs-twitter/pkg/jwt"
 	"github.com/golanguzb70/udevslabs-twitter/pkg/logger"
+	"github.com/redis/go-redis/v9"
 )
 
 // NewRouter -.
@@ -29,21 +32,23 @@ import (
 // @securityDefinitions.apikey BearerAuth
 // @in header
 // @name Authorization
-func NewRouter(engine *gin.Engine, l *logger.Logger, config *config.Config, useCase *usecase.UseCase, redis rediscache.RedisCache) {
+func NewRouter(engine *gin.Engine, l *logger.Logger, config *config.Config, useCase *usecase.UseCase, redisCache rediscache.RedisCache, redisClient *redis.Client, keyRing *jwt.KeyRing, enforcer *casbin.SyncedEnforcer, fanOut *worker.TimelineFanOut) {
 	// Options
 	engine.Use(gin.Logger())
 	engine.Use(gin.Recovery())
 
-	handlerV1 := handler.NewHandler(l, config, useCase, redis)
+	handlerV1 := handler.NewHandler(l, config, useCase, redisCache, redisClient, keyRing, enforcer, fanOut)
 
-	// Initialize Casbin enforcer
-	e := casbin.NewEnforcer("config/rbac.conf", "config/policy.csv")
-	engine.Use(handlerV1.AuthMiddleware(e))
+	engine.Use(handlerV1.AuthMiddleware(enforcer))
+	engine.Use(handlerV1.RateLimitMiddleware())
 
 	// Swagger
 	url := ginSwagger.URL("swagger/doc.json") // The url pointing to API definition
 	engine.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler, url))
 
+	// Public keys to verify access tokens
+	engine.GET("/.well-known/jwks.json", handlerV1.JWKS)
+
 	// K8s probe
 	engine.GET("/healthz", func(c *gin.Context) { c.Status(http.StatusOK) })
 
@@ -58,16 +63,18 @@ func NewRouter(engine *gin.Engine, l *logger.Logger, config *config.Config, useC
 		user.POST("/", handlerV1.CreateUser)
 		user.GET("/list", handlerV1.GetUsers)
 		user.GET("/:id", handlerV1.GetUser)
-		user.PUT("/", handlerV1.UpdateUser)
-		user.DELETE("/:id", handlerV1.DeleteUser)
+		user.PUT("/", handlerV1.OwnerOrAdmin(handlerV1.UserSelf(handler.BodyID("id"))), handlerV1.UpdateUser)
+		user.DELETE("/:id", handlerV1.NotImpersonated(), handlerV1.OwnerOrAdmin(handlerV1.UserSelf(handler.PathID("id"))), handlerV1.DeleteUser)
+		user.DELETE("/:id/lockout", handlerV1.ClearLockout)
 	}
 
 	session := v1.Group("/session")
 	{
 		session.GET("/list", handlerV1.GetSessions)
-		session.GET("/:id", handlerV1.GetSession)
+		session.POST("/revoke-others", handlerV1.RevokeOtherSessions)
+		session.GET("/:id", handlerV1.OwnerOrAdmin(handlerV1.SessionOwner(handler.PathID("id"))), handlerV1.GetSession)
 		session.PUT("/", handlerV1.UpdateSession)
-		session.DELETE("/:id", handlerV1.DeleteSession)
+		session.DELETE("/:id", handlerV1.OwnerOrAdmin(handlerV1.SessionOwner(handler.PathID("id"))), handlerV1.DeleteSession)
 	}
 
 	auth := v1.Group("/auth")
@@ -75,7 +82,50 @@ func NewRouter(engine *gin.Engine, l *logger.Logger, config *config.Config, useC
 		auth.POST("/logout", handlerV1.Logout)
 		auth.POST("/register", handlerV1.Register)
 		auth.POST("/verify-email", handlerV1.VerifyEmail)
+		auth.POST("/resend-otp", handlerV1.ResendOtp)
 		auth.POST("/login", handlerV1.Login)
+		auth.POST("/refresh", handlerV1.RefreshToken)
+		auth.POST("/forgot-password", handlerV1.ForgotPassword)
+		auth.POST("/reset-password", handlerV1.ResetPassword)
+		auth.POST("/mfa/enroll", handlerV1.MfaEnrollPending)
+		auth.POST("/mfa/verify", handlerV1.MfaVerify)
+	}
+
+	// credentials can't be changed while an admin impersonates the user
+	account := v1.Group("/account", handlerV1.NotImpersonated())
+	{
+		account.POST("/change-password", handlerV1.ChangePassword)
+		account.POST("/change-email", handlerV1.ChangeEmail)
+		account.POST("/change-email/verify", handlerV1.ChangeEmailVerify)
+		account.POST("/deactivate", handlerV1.DeactivateAccount)
+	}
+
+	mfa := v1.Group("/mfa", handlerV1.NotImpersonated())
+	{
+		mfa.POST("/enroll", handlerV1.MfaEnroll)
+		mfa.POST("/activate", handlerV1.MfaActivate)
+		mfa.POST("/recovery-codes", handlerV1.MfaRegenerateRecoveryCodes)
+		mfa.DELETE("/", handlerV1.MfaDisable)
+	}
+
+	tokens := v1.Group("/tokens")
+	{
+		tokens.POST("/", handlerV1.NotImpersonated(), handlerV1.CreateToken)
+		tokens.GET("/list", handlerV1.GetTokens)
+		tokens.DELETE("/:id", handlerV1.OwnerOrAdmin(handlerV1.TokenOwner(handler.PathID("id"))), handlerV1.DeleteToken)
+	}
+
+	impersonation := v1.Group("/impersonation")
+	{
+		impersonation.POST("/", handlerV1.StartImpersonation)
+		impersonation.GET("/audit", handlerV1.GetImpersonationAuditLogs)
+	}
+
+	policy := v1.Group("/policy")
+	{
+		policy.GET("/list", handlerV1.GetPolicies)
+		policy.POST("/", handlerV1.AddPolicy)
+		policy.DELETE("/", handlerV1.RemovePolicy)
 	}
 
 	tag := v1.Group("/tag")
@@ -97,9 +147,37 @@ func NewRouter(engine *gin.Engine, l *logger.Logger, config *config.Config, useC
 	{
 		tweet.POST("/", handlerV1.CreateTweet)
 		tweet.GET("/list", handlerV1.GetTweets)
+		tweet.GET("/scheduled/list", handlerV1.GetScheduledTweets)
 		tweet.GET("/:id", handlerV1.GetTweet)
-		tweet.PUT("/", handlerV1.UpdateTweet)
-		tweet.DELETE("/:id", handlerV1.DeleteTweet)
+		tweet.GET("/:id/replies", handlerV1.GetTweetReplies)
+		tweet.GET("/:id/conversation", handlerV1.GetConversation)
+		tweet.GET("/:id/history", handlerV1.GetTweetHistory)
+		tweet.POST("/:id/retweet", handlerV1.Retweet)
+		tweet.DELETE("/:id/retweet", handlerV1.Unretweet)
+		tweet.POST("/:id/like", handlerV1.LikeTweet)
+		tweet.DELETE("/:id/like", handlerV1.UnlikeTweet)
+		tweet.GET("/:id/likes", handlerV1.GetTweetLikes)
+		tweet.PUT("/", handlerV1.OwnerOrAdmin(handlerV1.TweetOwner(handler.BodyID("id"))), handlerV1.UpdateTweet)
+		tweet.DELETE("/:id", handlerV1.OwnerOrAdmin(handlerV1.TweetOwner(handler.PathID("id"))), handlerV1.DeleteTweet)
+		tweet.PUT("/:id/schedule", handlerV1.OwnerOrAdmin(handlerV1.TweetOwner(handler.PathID("id"))), handlerV1.ScheduleTweet)
+		tweet.DELETE("/:id/schedule", handlerV1.OwnerOrAdmin(handlerV1.TweetOwner(handler.PathID("id"))), handlerV1.CancelScheduledTweet)
+	}
+
+	bookmark := v1.Group("/bookmark")
+	{
+		bookmark.POST("/", handlerV1.ToggleBookmark)
+		bookmark.PUT("/", handlerV1.MoveBookmark)
+		bookmark.GET("/list", handlerV1.GetBookmarks)
+		bookmark.POST("/folder", handlerV1.CreateBookmarkFolder)
+		bookmark.GET("/folder/list", handlerV1.GetBookmarkFolders)
+		bookmark.PUT("/folder", handlerV1.OwnerOrAdmin(handlerV1.BookmarkFolderOwner(handler.BodyID("id"))), handlerV1.UpdateBookmarkFolder)
+		bookmark.DELETE("/folder/:id", handlerV1.OwnerOrAdmin(handlerV1.BookmarkFolderOwner(handler.PathID("id"))), handlerV1.DeleteBookmarkFolder)
+	}
+
+	timeline := v1.Group("/timeline")
+	{
+		timeline.GET("/home", handlerV1.GetHomeTimeline)
+		timeline.GET("/mentions", handlerV1.GetMentionsTimeline)
 	}
 
 }
@@ -0,0 +1,21 @@
+package entity
+
+type Bookmark struct {
+	TweetId    string `json:"tweet_id"`
+	FolderId   string `json:"folder_id"`
+	UserId     string `json:"-"`
+	Bookmarked bool   `json:"bookmarked"`
+}
+
+type BookmarkFolder struct {
+	Id        string `json:"id"`
+	UserId    string `json:"user_id"`
+	Name      string `json:"name"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+type BookmarkFolderList struct {
+	Items []BookmarkFolder `json:"folders"`
+	Count int              `json:"count"`
+}
@@ -0,0 +1,25 @@
+package entity
+
+type ImpersonationRequest struct {
+	UserID string `json:"user_id"`
+	Reason string `json:"reason"`
+}
+
+// ImpersonationAuditLog is a request made with an impersonation session
+type ImpersonationAuditLog struct {
+	ID         string `json:"id"`
+	SessionID  string `json:"session_id"`
+	ActorID    string `json:"actor_id"`
+	UserID     string `json:"user_id"`
+	Method     string `json:"method"`
+	Path       string `json:"path"`
+	StatusCode int    `json:"status_code"`
+	IPAddress  string `json:"ip_address"`
+	Details    string `json:"details"`
+	CreatedAt  string `json:"created_at"`
+}
+
+type ImpersonationAuditLogList struct {
+	Items []ImpersonationAuditLog `json:"logs"`
+	Count int                     `json:"count"`
+}
@@ -0,0 +1,44 @@
+package entity
+
+type MfaRecoveryCode struct {
+	ID        string `json:"id"`
+	UserID    string `json:"user_id"`
+	CodeHash  string `json:"-"`
+	IsUsed    bool   `json:"is_used"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+type MfaRecoveryCodeReplaceRequest struct {
+	UserID     string   `json:"user_id"`
+	CodeHashes []string `json:"code_hashes"`
+}
+
+type MfaChallengeResponse struct {
+	MfaToken           string `json:"mfa_token"`
+	EnrollmentRequired bool   `json:"enrollment_required"`
+	ExpiresAt          string `json:"expires_at"`
+}
+
+type MfaEnrollRequest struct {
+	MfaToken string `json:"mfa_token"`
+}
+
+type MfaEnrollResponse struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+type MfaVerifyRequest struct {
+	MfaToken     string `json:"mfa_token"`
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recovery_code"`
+}
+
+type MfaCodeRequest struct {
+	Code string `json:"code"`
+}
+
+type MfaRecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
@@ -12,15 +12,17 @@ type OrderBy struct {
 
 type Filter struct {
 	Column string `json:"column"`
-	Type   string `json:"type"` // eq, ne, gt, gte, lt, lte, search
+	Type   string `json:"type"` // eq, ne, gt, gte, lt, lte, search, in (comma separated values)
 	Value  string `json:"value"`
 }
 
 type GetListFilter struct {
-	Page    int       `json:"offset"`
-	Limit   int       `json:"limit"`
-	Filters []Filter  `json:"filters"`
-	OrderBy []OrderBy `json:"order_by"`
+	Page      int       `json:"offset"`
+	Limit     int       `json:"limit"`
+	Cursor    string    `json:"cursor"`     // next_cursor or prev_cursor of a page, page is ignored when set
+	SkipCount bool      `json:"skip_count"` // count is left 0
+	Filters   []Filter  `json:"filters"`
+	OrderBy   []OrderBy `json:"order_by"`
 }
 
 type UpdateFieldItem struct {
@@ -45,3 +47,14 @@ type ErrorResponse struct {
 type SuccessResponse struct {
 	Message string `json:"message"`
 }
+
+type PolicyRule struct {
+	// Ptype is "p" for permissions (sub, obj, act) and "g" for roles (user, role)
+	Ptype string   `json:"ptype"`
+	Rule  []string `json:"rule"`
+}
+
+type PolicyList struct {
+	Policies         [][]string `json:"policies"`
+	GroupingPolicies [][]string `json:"grouping_policies"`
+}
@@ -9,11 +9,40 @@ type Session struct {
 	ExpiresAt    string `json:"expires_at"`
 	LastActiveAt string `json:"last_active_at"`
 	Platform     string `json:"platform"`
-	CreatedAt    string `json:"created_at"`
-	UpdatedAt    string `json:"updated_at"`
+	// ImpersonatorID is the admin acting as the user, empty for sessions of the user
+	ImpersonatorID string `json:"impersonator_id"`
+	Device         Device `json:"device"`
+	IsCurrent      bool   `json:"is_current"`
+	CreatedAt      string `json:"created_at"`
+	UpdatedAt      string `json:"updated_at"`
+}
+
+// Device is parsed from the user agent of the session
+type Device struct {
+	Name    string `json:"name"`
+	Browser string `json:"browser"`
+	OS      string `json:"os"`
+	App     string `json:"app"`
+	Mobile  bool   `json:"mobile"`
 }
 
 type SessionList struct {
-	Items []Session `json:"sessions"`
-	Count int       `json:"count"`
+	Items      []Session `json:"sessions"`
+	Count      int       `json:"count"`
+	NextCursor string    `json:"next_cursor,omitempty"`
+	PrevCursor string    `json:"prev_cursor,omitempty"`
+}
+
+type RefreshToken struct {
+	ID        string `json:"id"`
+	SessionID string `json:"session_id"`
+	TokenHash string `json:"-"`
+	IsUsed    bool   `json:"is_used"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+type RefreshTokenSingleRequest struct {
+	ID        string `json:"id"`
+	TokenHash string `json:"token_hash"`
 }
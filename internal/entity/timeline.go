@@ -0,0 +1,33 @@
+package entity
+
+// TimelineEntry is a tweet on a timeline, the score is the publish time in unix milliseconds
+type TimelineEntry struct {
+	TweetId string `json:"tweet_id"`
+	Score   int64  `json:"score"`
+}
+
+// TimelineRequest selects published tweets, newest first
+type TimelineRequest struct {
+	UserId   string   `json:"user_id"`   // home timeline of the user, its own tweets and those of followed accounts
+	OwnerIds []string `json:"owner_ids"` // tweets of these accounts only
+	Before   int64    `json:"before"`    // score cursor, only older tweets are returned, 0 for the newest
+	Limit    int      `json:"limit"`
+}
+
+// FollowerIdsRequest pages through the followers of an account ordered by id
+type FollowerIdsRequest struct {
+	UserId string `json:"user_id"`
+	After  string `json:"after"`
+	Limit  int    `json:"limit"`
+}
+
+// FollowedAmongRequest asks which of the accounts the user follows
+type FollowedAmongRequest struct {
+	UserId  string   `json:"user_id"`
+	UserIds []string `json:"user_ids"`
+}
+
+type Timeline struct {
+	Items      []Tweet `json:"items"`
+	NextCursor string  `json:"next_cursor"` // pass as cursor to get the next page, empty on the last page
+}
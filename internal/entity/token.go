@@ -0,0 +1,31 @@
+package entity
+
+type PersonalAccessToken struct {
+	ID         string   `json:"id"`
+	UserID     string   `json:"user_id"`
+	Name       string   `json:"name"`
+	Token      string   `json:"token,omitempty"`
+	TokenHash  string   `json:"-"`
+	Scopes     []string `json:"scopes"`
+	ExpiresAt  string   `json:"expires_at"`
+	LastUsedAt string   `json:"last_used_at"`
+	LastUsedIP string   `json:"last_used_ip"`
+	CreatedAt  string   `json:"created_at"`
+	UpdatedAt  string   `json:"updated_at"`
+}
+
+type PersonalAccessTokenCreateRequest struct {
+	Name      string   `json:"name"`
+	Scopes    []string `json:"scopes"`
+	ExpiresAt string   `json:"expires_at"`
+}
+
+type PersonalAccessTokenSingleRequest struct {
+	ID        string `json:"id"`
+	TokenHash string `json:"token_hash"`
+}
+
+type PersonalAccessTokenList struct {
+	Items []PersonalAccessToken `json:"tokens"`
+	Count int                   `json:"count"`
+}
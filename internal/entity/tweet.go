@@ -20,17 +20,102 @@ type AttachmentMultipleInsertRequest struct {
 }
 
 type Tweet struct {
+	Id             string              `json:"id"`
+	Owner          User                `json:"owner"`
+	Content        string              `json:"content"`
+	Tags           map[string][]string `json:"tags"`
+	Attachments    []Attachment        `json:"attachments"`
+	Entities       TweetEntities       `json:"entities"`
+	Status         string              `json:"status"`
+	PublishAt      string              `json:"publish_at"`         // required with status scheduled
+	ReplyTo        string              `json:"reply_to,omitempty"` // id of the tweet to reply to, only on create
+	ParentId       string              `json:"parent_id"`
+	ConversationId string              `json:"conversation_id"`
+	ReplyCount     int                 `json:"reply_count"`
+	Replies        []Tweet             `json:"replies,omitempty"` // only in the conversation view
+	QuoteOfId      string              `json:"quote_of_id"`       // set on create to quote a tweet
+	RetweetOfId    string              `json:"retweet_of_id"`
+	RetweetCount   int                 `json:"retweet_count"`
+	QuoteCount     int                 `json:"quote_count"`
+	LikeCount      int                 `json:"like_count"`
+	EditedAt       string              `json:"edited_at"`
+	RevisionCount  int                 `json:"revision_count"`
+	EditorId       string              `json:"-"` // user making an update, recorded in the revision
+	Published      bool                `json:"-"` // set by Update and Retweet when the call published the tweet
+	LikedByMe      bool                `json:"liked_by_me"`
+	BookmarkedByMe bool                `json:"bookmarked_by_me"`
+	Original       *Tweet              `json:"original,omitempty"` // the retweeted or quoted tweet
+	CreatedAt      string              `json:"created_at"`
+	UpdatedAt      string              `json:"updated_at"`
+}
+
+// TweetEntities are the parts of the content that refer to something, offsets are in unicode code points
+type TweetEntities struct {
+	Mentions []Mention `json:"mentions"`
+}
+
+// Mention is an @username in the content that belongs to a user, End is exclusive
+type Mention struct {
+	UserId   string `json:"user_id"`
+	Username string `json:"username"`
+	Start    int    `json:"start"`
+	End      int    `json:"end"`
+}
+
+type TweetList struct {
+	Items      []Tweet `json:"items"`
+	Count      int64   `json:"count"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+	PrevCursor string  `json:"prev_cursor,omitempty"`
+}
+
+// TweetRevision is a prior version of a tweet, replaced by the editor at created_at
+type TweetRevision struct {
 	Id          string              `json:"id"`
-	Owner       User                `json:"owner"`
+	TweetId     string              `json:"tweet_id"`
+	Revision    int                 `json:"revision"`
 	Content     string              `json:"content"`
 	Tags        map[string][]string `json:"tags"`
 	Attachments []Attachment        `json:"attachments"`
-	Status      string              `json:"status"`
+	EditorId    string              `json:"editor_id"`
 	CreatedAt   string              `json:"created_at"`
-	UpdatedAt   string              `json:"updated_at"`
 }
 
-type TweetList struct {
-	Items []Tweet `json:"items"`
-	Count int64   `json:"count"`
+type TweetRevisionList struct {
+	Items []TweetRevision `json:"revisions"`
+	Count int             `json:"count"`
+}
+
+type ScheduleTweetRequest struct {
+	Id        string `json:"-"`
+	PublishAt string `json:"publish_at"`
+}
+
+// PublishScheduledRequest publishes up to Limit scheduled tweets that are due
+type PublishScheduledRequest struct {
+	Limit int `json:"limit"`
+}
+
+type TweetLike struct {
+	TweetId   string `json:"tweet_id"`
+	UserId    string `json:"user_id"`
+	Liked     bool   `json:"liked"`
+	LikeCount int    `json:"like_count"`
+}
+
+// UserTweetsRequest asks which of the tweets the user liked or bookmarked
+type UserTweetsRequest struct {
+	UserId   string   `json:"user_id"`
+	TweetIds []string `json:"tweet_ids"`
+}
+
+type RetweetRequest struct {
+	UserId  string `json:"user_id"`
+	TweetId string `json:"tweet_id"`
+}
+
+// Conversation is the thread tree of a tweet, Root is the tweet that started it
+type Conversation struct {
+	Root  Tweet `json:"root"`
+	Count int64 `json:"count"`
 }
@@ -1,19 +1,24 @@
 package entity
 
 type User struct {
-	ID          string `json:"id"`
-	FullName    string `json:"full_name"`
-	Username    string `json:"username"`
-	Email       string `json:"email"`
-	Password    string `json:"password"`
-	UserType    string `json:"user_type"`
-	UserRole    string `json:"user_role"`
-	Status      string `json:"status"`
-	AccessToken string `json:"access_token"`
-	AvatarId    string `json:"avatar_id"`
-	Gender      string `json:"gender"`
-	CreatedAt   string `json:"created_at"`
-	UpdatedAt   string `json:"updated_at"`
+	ID            string `json:"id"`
+	FullName      string `json:"full_name"`
+	Username      string `json:"username"`
+	Email         string `json:"email"`
+	Password      string `json:"password"`
+	UserType      string `json:"user_type"`
+	UserRole      string `json:"user_role"`
+	Status        string `json:"status"`
+	AccessToken   string `json:"access_token"`
+	RefreshToken  string `json:"refresh_token"`
+	AvatarId      string `json:"avatar_id"`
+	Gender        string `json:"gender"`
+	TotpEnabled   bool   `json:"totp_enabled"`
+	TotpSecret    string `json:"-"`
+	DeactivatedAt string `json:"deactivated_at"`
+	DeleteAfter   string `json:"delete_after"`
+	CreatedAt     string `json:"created_at"`
+	UpdatedAt     string `json:"updated_at"`
 }
 
 type UserSingleRequest struct {
@@ -23,6 +28,24 @@ type UserSingleRequest struct {
 }
 
 type UserList struct {
-	Items []User `json:"users"`
-	Count int    `json:"count"`
+	Items      []User `json:"users"`
+	Count      int    `json:"count"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+}
+
+type UserPurgeRequest struct {
+	// ID purges the user right away, otherwise up to Limit deactivated users past delete_after are purged
+	ID    string `json:"id"`
+	Limit int    `json:"limit"`
+}
+
+type UserPurgeResult struct {
+	UserIDs    []string `json:"user_ids"`
+	FilePaths  []string `json:"file_paths"`
+	SessionIDs []string `json:"session_ids"` // deleted sessions, they are still cached
+}
+
+type DeactivateAccountRequest struct {
+	Password string `json:"password"`
 }
@@ -18,6 +18,7 @@ type (
 		Update(ctx context.Context, req entity.User) (entity.User, error)
 		Delete(ctx context.Context, req entity.Id) error
 		UpdateField(ctx context.Context, req entity.UpdateFieldRequest) (entity.RowsEffected, error)
+		Purge(ctx context.Context, req entity.UserPurgeRequest) (entity.UserPurgeResult, error)
 	}
 
 	// SessionRepo -.
@@ -28,6 +29,35 @@ type (
 		Update(ctx context.Context, req entity.Session) (entity.Session, error)
 		Delete(ctx context.Context, req entity.Id) error
 		UpdateField(ctx context.Context, req entity.UpdateFieldRequest) (entity.RowsEffected, error)
+		Invalidate(ctx context.Context, ids []string)
+	}
+
+	// Refresh Token Repo
+	RefreshTokenRepoI interface {
+		Create(ctx context.Context, req entity.RefreshToken) (entity.RefreshToken, error)
+		GetSingle(ctx context.Context, req entity.RefreshTokenSingleRequest) (entity.RefreshToken, error)
+		UpdateField(ctx context.Context, req entity.UpdateFieldRequest) (entity.RowsEffected, error)
+	}
+
+	// Mfa Recovery Code Repo
+	MfaRecoveryCodeRepoI interface {
+		Replace(ctx context.Context, req entity.MfaRecoveryCodeReplaceRequest) error
+		UpdateField(ctx context.Context, req entity.UpdateFieldRequest) (entity.RowsEffected, error)
+	}
+
+	// Personal Access Token Repo
+	PersonalAccessTokenRepoI interface {
+		Create(ctx context.Context, req entity.PersonalAccessToken) (entity.PersonalAccessToken, error)
+		GetSingle(ctx context.Context, req entity.PersonalAccessTokenSingleRequest) (entity.PersonalAccessToken, error)
+		GetList(ctx context.Context, req entity.GetListFilter) (entity.PersonalAccessTokenList, error)
+		Delete(ctx context.Context, req entity.Id) error
+		UpdateField(ctx context.Context, req entity.UpdateFieldRequest) (entity.RowsEffected, error)
+	}
+
+	// Impersonation Audit Log Repo
+	ImpersonationAuditLogRepoI interface {
+		Create(ctx context.Context, req entity.ImpersonationAuditLog) (entity.ImpersonationAuditLog, error)
+		GetList(ctx context.Context, req entity.GetListFilter) (entity.ImpersonationAuditLogList, error)
 	}
 
 	// Tag Repo
@@ -51,6 +81,9 @@ type (
 	FollowerRepoI interface {
 		UpsertOrRemove(ctx context.Context, req entity.Follower) (entity.Follower, error)
 		GetList(ctx context.Context, req entity.GetListFilter) (entity.UserList, error)
+		FollowerCount(ctx context.Context, req entity.Id) (int, error)
+		FollowerIds(ctx context.Context, req entity.FollowerIdsRequest) ([]string, error)
+		FollowedAmong(ctx context.Context, req entity.FollowedAmongRequest) ([]string, error)
 	}
 
 	// Tweet attachment
@@ -70,5 +103,43 @@ type (
 		Update(ctx context.Context, req entity.Tweet) (entity.Tweet, error)
 		Delete(ctx context.Context, req entity.Id) error
 		UpdateField(ctx context.Context, req entity.UpdateFieldRequest) (entity.RowsEffected, error)
+		GetBookmarks(ctx context.Context, req entity.GetListFilter) (entity.TweetList, error)
+		GetMentions(ctx context.Context, req entity.GetListFilter) (entity.TweetList, error)
+		Retweet(ctx context.Context, req entity.RetweetRequest) (entity.Tweet, error)
+		Unretweet(ctx context.Context, req entity.RetweetRequest) error
+		TimelineEntries(ctx context.Context, req entity.TimelineRequest) ([]entity.TimelineEntry, error)
+		GetScheduled(ctx context.Context, req entity.GetListFilter) (entity.TweetList, error)
+		Schedule(ctx context.Context, req entity.ScheduleTweetRequest) (entity.Tweet, error)
+		Unschedule(ctx context.Context, req entity.Id) (entity.Tweet, error)
+		PublishScheduled(ctx context.Context, req entity.PublishScheduledRequest) ([]entity.Tweet, error)
+	}
+
+	// Tweet revision
+	TweetRevisionRepoI interface {
+		GetList(ctx context.Context, req entity.GetListFilter) (entity.TweetRevisionList, error)
+	}
+
+	// Bookmark
+	BookmarkRepoI interface {
+		UpsertOrRemove(ctx context.Context, req entity.Bookmark) (entity.Bookmark, error)
+		UpdateField(ctx context.Context, req entity.UpdateFieldRequest) (entity.RowsEffected, error)
+		BookmarkedTweets(ctx context.Context, req entity.UserTweetsRequest) (map[string]bool, error)
+	}
+
+	// Bookmark folder
+	BookmarkFolderRepoI interface {
+		Create(ctx context.Context, req entity.BookmarkFolder) (entity.BookmarkFolder, error)
+		GetSingle(ctx context.Context, req entity.Id) (entity.BookmarkFolder, error)
+		GetList(ctx context.Context, req entity.GetListFilter) (entity.BookmarkFolderList, error)
+		Update(ctx context.Context, req entity.BookmarkFolder) (entity.BookmarkFolder, error)
+		Delete(ctx context.Context, req entity.Id) error
+	}
+
+	// Tweet like
+	TweetLikeRepoI interface {
+		Like(ctx context.Context, req entity.TweetLike) (entity.TweetLike, error)
+		Unlike(ctx context.Context, req entity.TweetLike) (entity.TweetLike, error)
+		GetList(ctx context.Context, req entity.GetListFilter) (entity.UserList, error)
+		LikedTweets(ctx context.Context, req entity.UserTweetsRequest) (map[string]bool, error)
 	}
 )
@@ -1,6 +1,7 @@
 package usecase
 
 import (
+	rediscache "github.com/golanguzb70/redis-cache"
 	"github.com/golanguzb70/udevslabs-twitter/config"
 	"github.com/golanguzb70/udevslabs-twitter/internal/usecase/repo"
 	"github.com/golanguzb70/udevslabs-twitter/pkg/logger"
@@ -9,24 +10,40 @@ import (
 
 // UseCase -.
 type UseCase struct {
-	UserRepo             UserRepoI
-	SessionRepo          SessionRepoI
-	TagRepo              TagRepoI
-	UserTagRepo          UserTagRepoI
-	FollowerRepo         FollowerRepoI
-	TweetAttachmentsRepo TweetAttachentRepoI
-	TweetRepo            TweetI
+	UserRepo                  UserRepoI
+	SessionRepo               SessionRepoI
+	RefreshTokenRepo          RefreshTokenRepoI
+	MfaRecoveryCodeRepo       MfaRecoveryCodeRepoI
+	PersonalAccessTokenRepo   PersonalAccessTokenRepoI
+	ImpersonationAuditLogRepo ImpersonationAuditLogRepoI
+	TagRepo                   TagRepoI
+	UserTagRepo               UserTagRepoI
+	FollowerRepo              FollowerRepoI
+	TweetAttachmentsRepo      TweetAttachentRepoI
+	TweetRepo                 TweetI
+	TweetLikeRepo             TweetLikeRepoI
+	TweetRevisionRepo         TweetRevisionRepoI
+	BookmarkRepo              BookmarkRepoI
+	BookmarkFolderRepo        BookmarkFolderRepoI
 }
 
 // New -.
-func New(pg *postgres.Postgres, config *config.Config, logger *logger.Logger) *UseCase {
+func New(pg *postgres.Postgres, config *config.Config, logger *logger.Logger, redis rediscache.RedisCache) *UseCase {
 	return &UseCase{
-		UserRepo:             repo.NewUserRepo(pg, config, logger),
-		SessionRepo:          repo.NewSessionRepo(pg, config, logger),
-		TagRepo:              repo.NewTagRepo(pg, config, logger),
-		UserTagRepo:          repo.NewUserTagRepo(pg, config, logger),
-		FollowerRepo:         repo.NewFollowerRepo(pg, config, logger),
-		TweetAttachmentsRepo: repo.NewAttachmentRepo(pg, config, logger),
-		TweetRepo:            repo.NewTweetRepo(pg, config, logger),
+		UserRepo:                  repo.NewUserRepo(pg, config, logger),
+		SessionRepo:               repo.NewSessionRepo(pg, config, logger, redis),
+		RefreshTokenRepo:          repo.NewRefreshTokenRepo(pg, config, logger),
+		MfaRecoveryCodeRepo:       repo.NewMfaRecoveryCodeRepo(pg, config, logger),
+		PersonalAccessTokenRepo:   repo.NewPersonalAccessTokenRepo(pg, config, logger),
+		ImpersonationAuditLogRepo: repo.NewImpersonationAuditLogRepo(pg, config, logger),
+		TagRepo:                   repo.NewTagRepo(pg, config, logger),
+		UserTagRepo:               repo.NewUserTagRepo(pg, config, logger),
+		FollowerRepo:              repo.NewFollowerRepo(pg, config, logger),
+		TweetAttachmentsRepo:      repo.NewAttachmentRepo(pg, config, logger),
+		TweetRepo:                 repo.NewTweetRepo(pg, config, logger),
+		TweetLikeRepo:             repo.NewTweetLikeRepo(pg, config, logger),
+		TweetRevisionRepo:         repo.NewTweetRevisionRepo(pg, config, logger),
+		BookmarkRepo:              repo.NewBookmarkRepo(pg, config, logger),
+		BookmarkFolderRepo:        repo.NewBookmarkFolderRepo(pg, config, logger),
 	}
 }
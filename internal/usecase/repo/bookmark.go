@@ -0,0 +1,121 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/golanguzb70/udevslabs-twitter/config"
+	"github.com/golanguzb70/udevslabs-twitter/internal/entity"
+	"github.com/golanguzb70/udevslabs-twitter/pkg/logger"
+	"github.com/golanguzb70/udevslabs-twitter/pkg/postgres"
+	"github.com/google/uuid"
+	"github.com/jackc/pgconn"
+)
+
+type BookmarkRepo struct {
+	pg     *postgres.Postgres
+	config *config.Config
+	logger *logger.Logger
+}
+
+// New -.
+func NewBookmarkRepo(pg *postgres.Postgres, config *config.Config, logger *logger.Logger) *BookmarkRepo {
+	return &BookmarkRepo{
+		pg:     pg,
+		config: config,
+		logger: logger,
+	}
+}
+
+// UpsertOrRemove bookmarks the tweet, or removes the bookmark if the user already has one.
+func (r *BookmarkRepo) UpsertOrRemove(ctx context.Context, req entity.Bookmark) (entity.Bookmark, error) {
+	query, args, err := r.pg.Builder.Insert("bookmark").
+		Columns(`id, user_id, tweet_id, folder_id`).
+		Values(uuid.NewString(), req.UserId, req.TweetId, sql.NullString{String: req.FolderId, Valid: req.FolderId != ""}).ToSql()
+	if err != nil {
+		return req, err
+	}
+
+	_, err = r.pg.Pool.Exec(ctx, query, args...)
+	if e, ok := err.(*pgconn.PgError); ok && e.Code == "23505" {
+		query, args, err = r.pg.Builder.Delete("bookmark").Where(
+			squirrel.Eq{
+				"user_id":  req.UserId,
+				"tweet_id": req.TweetId,
+			}).ToSql()
+		if err != nil {
+			return req, err
+		}
+
+		_, err = r.pg.Pool.Exec(ctx, query, args...)
+		if err != nil {
+			return req, err
+		}
+
+		req.Bookmarked, req.FolderId = false, ""
+
+		return req, nil
+	}
+	if err != nil {
+		return req, err
+	}
+
+	req.Bookmarked = true
+
+	return req, nil
+}
+
+func (r *BookmarkRepo) UpdateField(ctx context.Context, req entity.UpdateFieldRequest) (entity.RowsEffected, error) {
+	mp := map[string]interface{}{}
+	response := entity.RowsEffected{}
+
+	for _, item := range req.Items {
+		mp[item.Column] = item.Value
+	}
+
+	qeury, args, err := r.pg.Builder.Update("bookmark").SetMap(mp).Where(PrepareFilter(req.Filter)).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	n, err := r.pg.Pool.Exec(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+
+	response.RowsEffected = int(n.RowsAffected())
+
+	return response, nil
+}
+
+// BookmarkedTweets returns the ids of the tweets among req.TweetIds the user bookmarked.
+func (r *BookmarkRepo) BookmarkedTweets(ctx context.Context, req entity.UserTweetsRequest) (map[string]bool, error) {
+	response := make(map[string]bool)
+	if req.UserId == "" || len(req.TweetIds) == 0 {
+		return response, nil
+	}
+
+	qeury, args, err := r.pg.Builder.Select("tweet_id").From("bookmark").
+		Where("user_id = ? AND tweet_id = ANY(?::uuid[])", req.UserId, req.TweetIds).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := r.pg.Pool.Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tweetID string
+		if err = rows.Scan(&tweetID); err != nil {
+			return response, err
+		}
+
+		response[tweetID] = true
+	}
+
+	return response, rows.Err()
+}
@@ -0,0 +1,152 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/golanguzb70/udevslabs-twitter/config"
+	"github.com/golanguzb70/udevslabs-twitter/internal/entity"
+	"github.com/golanguzb70/udevslabs-twitter/pkg/logger"
+	"github.com/golanguzb70/udevslabs-twitter/pkg/postgres"
+	"github.com/google/uuid"
+)
+
+type BookmarkFolderRepo struct {
+	pg     *postgres.Postgres
+	config *config.Config
+	logger *logger.Logger
+}
+
+// New -.
+func NewBookmarkFolderRepo(pg *postgres.Postgres, config *config.Config, logger *logger.Logger) *BookmarkFolderRepo {
+	return &BookmarkFolderRepo{
+		pg:     pg,
+		config: config,
+		logger: logger,
+	}
+}
+
+func (r *BookmarkFolderRepo) Create(ctx context.Context, req entity.BookmarkFolder) (entity.BookmarkFolder, error) {
+	req.Id = uuid.NewString()
+
+	qeury, args, err := r.pg.Builder.Insert("bookmark_folder").
+		Columns(`id, user_id, name`).
+		Values(req.Id, req.UserId, req.Name).ToSql()
+	if err != nil {
+		return entity.BookmarkFolder{}, err
+	}
+
+	_, err = r.pg.Pool.Exec(ctx, qeury, args...)
+	if err != nil {
+		return entity.BookmarkFolder{}, err
+	}
+
+	return req, nil
+}
+
+func (r *BookmarkFolderRepo) GetSingle(ctx context.Context, req entity.Id) (entity.BookmarkFolder, error) {
+	response := entity.BookmarkFolder{}
+	var (
+		createdAt, updatedAt time.Time
+	)
+
+	qeury, args, err := r.pg.Builder.
+		Select(`id, user_id, name, created_at, updated_at`).
+		From("bookmark_folder").Where("id = ?", req.ID).ToSql()
+	if err != nil {
+		return entity.BookmarkFolder{}, err
+	}
+
+	err = r.pg.Pool.QueryRow(ctx, qeury, args...).
+		Scan(&response.Id, &response.UserId, &response.Name, &createdAt, &updatedAt)
+	if err != nil {
+		return entity.BookmarkFolder{}, err
+	}
+
+	response.CreatedAt = createdAt.Format(time.RFC3339)
+	response.UpdatedAt = updatedAt.Format(time.RFC3339)
+
+	return response, nil
+}
+
+func (r *BookmarkFolderRepo) GetList(ctx context.Context, req entity.GetListFilter) (entity.BookmarkFolderList, error) {
+	var (
+		response             = entity.BookmarkFolderList{}
+		createdAt, updatedAt time.Time
+	)
+
+	qeuryBuilder := r.pg.Builder.
+		Select(`id, user_id, name, created_at, updated_at`).
+		From("bookmark_folder")
+
+	qeuryBuilder, where := PrepareGetListQuery(qeuryBuilder, req)
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := r.pg.Pool.Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item entity.BookmarkFolder
+		err = rows.Scan(&item.Id, &item.UserId, &item.Name, &createdAt, &updatedAt)
+		if err != nil {
+			return response, err
+		}
+
+		item.CreatedAt = createdAt.Format(time.RFC3339)
+		item.UpdatedAt = updatedAt.Format(time.RFC3339)
+
+		response.Items = append(response.Items, item)
+	}
+
+	countQuery, args, err := r.pg.Builder.Select("COUNT(1)").From("bookmark_folder").Where(where).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	err = r.pg.Pool.QueryRow(ctx, countQuery, args...).Scan(&response.Count)
+	if err != nil {
+		return response, err
+	}
+
+	return response, nil
+}
+
+func (r *BookmarkFolderRepo) Update(ctx context.Context, req entity.BookmarkFolder) (entity.BookmarkFolder, error) {
+	mp := map[string]interface{}{
+		"name":       req.Name,
+		"updated_at": "now()",
+	}
+
+	qeury, args, err := r.pg.Builder.Update("bookmark_folder").SetMap(mp).Where("id = ?", req.Id).ToSql()
+	if err != nil {
+		return entity.BookmarkFolder{}, err
+	}
+
+	_, err = r.pg.Pool.Exec(ctx, qeury, args...)
+	if err != nil {
+		return entity.BookmarkFolder{}, err
+	}
+
+	return req, nil
+}
+
+func (r *BookmarkFolderRepo) Delete(ctx context.Context, req entity.Id) error {
+	qeury, args, err := r.pg.Builder.Delete("bookmark_folder").Where("id = ?", req.ID).ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.pg.Pool.Exec(ctx, qeury, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package repo
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/golanguzb70/udevslabs-twitter/internal/entity"
+)
+
+// cursor is the position of a row in a list ordered by created_at and id,
+// prev cursors point backwards from the first row of a page.
+type cursor struct {
+	CreatedAt time.Time `json:"t"`
+	Id        string    `json:"i"`
+	Prev      bool      `json:"p,omitempty"`
+}
+
+func encodeCursor(c cursor) string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(s string) (cursor, error) {
+	var c cursor
+
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || json.Unmarshal(b, &c) != nil || c.Id == "" {
+		return c, fmt.Errorf("%sinvalid cursor", "BAD_REQUEST")
+	}
+
+	return c, nil
+}
+
+// PrepareCursorQuery is PrepareGetListQuery for lists paged by cursor. The rows are ordered by the created_at and id
+// columns in the direction of the first order by of the request, newest first by default. Pages start after
+// req.Cursor if it is set and at req.Page otherwise, one extra row is fetched to tell if there is a next page.
+// The returned where doesn't include the cursor, so it counts the whole list.
+func PrepareCursorQuery(selectQuery squirrel.SelectBuilder, req entity.GetListFilter, createdAt, id string) (squirrel.SelectBuilder, squirrel.And, error) {
+	where := PrepareFilter(req.Filters)
+	selectQuery = selectQuery.Where(where)
+
+	if req.Limit <= 0 {
+		req.Limit = 10
+	}
+
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+
+	desc := len(req.OrderBy) == 0 || !strings.EqualFold(req.OrderBy[0].Order, "asc")
+
+	var c cursor
+	if req.Cursor != "" {
+		var err error
+		c, err = decodeCursor(req.Cursor)
+		if err != nil {
+			return selectQuery, where, err
+		}
+
+		// prev pages are read backwards from the cursor and reversed by cursorPage
+		op := ">"
+		if desc != c.Prev {
+			op = "<"
+		}
+
+		selectQuery = selectQuery.Where(fmt.Sprintf("(%s, %s) %s (?, ?)", createdAt, id, op), c.CreatedAt, c.Id)
+	} else {
+		selectQuery = selectQuery.Offset(uint64((req.Page - 1) * req.Limit))
+	}
+
+	order := " ASC"
+	if desc != c.Prev {
+		order = " DESC"
+	}
+
+	return selectQuery.OrderBy(createdAt+order, id+order).Limit(uint64(req.Limit + 1)), where, nil
+}
+
+// cursorPage trims the extra row fetched by PrepareCursorQuery, puts the rows of a prev page back in list order
+// and returns the cursors of the neighbouring pages. positions hold the created_at and id of the items.
+func cursorPage[T any](req entity.GetListFilter, items []T, positions []cursor) (page []T, next, prev string) {
+	if req.Limit <= 0 {
+		req.Limit = 10
+	}
+
+	// the cursor was validated by PrepareCursorQuery
+	c, _ := decodeCursor(req.Cursor)
+
+	more := len(items) > req.Limit
+	if more {
+		items, positions = items[:req.Limit], positions[:req.Limit]
+	}
+
+	if c.Prev {
+		slices.Reverse(items)
+		slices.Reverse(positions)
+	}
+
+	if len(items) == 0 {
+		return items, "", ""
+	}
+
+	first, last := positions[0], positions[len(positions)-1]
+	first.Prev, last.Prev = true, false
+
+	// pages reached backwards have a next page, pages reached forwards or by a later offset have a prev one
+	if more || c.Prev {
+		next = encodeCursor(last)
+	}
+
+	if (more && c.Prev) || (!c.Prev && (req.Cursor != "" || req.Page > 1)) {
+		prev = encodeCursor(first)
+	}
+
+	return items, next, prev
+}
@@ -59,6 +59,7 @@ func (r *FollowerRepo) UpsertOrRemove(ctx context.Context, req entity.Follower)
 				_, err = r.pg.Pool.Exec(ctx, query, args...)
 				if err == nil {
 					req.UnFollowed = true
+					return req, nil
 				}
 			}
 		}
@@ -73,6 +74,7 @@ func (r *FollowerRepo) GetList(ctx context.Context, req entity.GetListFilter) (e
 	var (
 		response             = entity.UserList{}
 		createdAt, updatedAt time.Time
+		positions            []cursor
 	)
 
 	followingId := ""
@@ -95,11 +97,23 @@ func (r *FollowerRepo) GetList(ctx context.Context, req entity.GetListFilter) (e
 		})
 	}
 
+	// deactivated accounts are hidden from follower lists
+	req.Filters = append(req.Filters, entity.Filter{
+		Column: "u.status",
+		Type:   "neq",
+		Value:  "deactivated",
+	})
+
 	qeuryBuilder := r.pg.Builder.
-		Select(`id, full_name, email, username, user_type, user_role, status, avatar_id, gender, created_at, updated_at`).
+		Select(`u.id, u.full_name, u.email, u.username, u.user_type, u.user_role, u.status, u.avatar_id, u.gender,
+			u.created_at, u.updated_at, f.created_at, f.id`).
 		From("follower f").Join("users as u ON u.id=f.follower_id")
 
-	qeuryBuilder, where := PrepareGetListQuery(qeuryBuilder, req)
+	// followers are paged by the time they followed
+	qeuryBuilder, where, err := PrepareCursorQuery(qeuryBuilder, req, "f.created_at", "f.id")
+	if err != nil {
+		return response, err
+	}
 
 	qeury, args, err := qeuryBuilder.ToSql()
 	if err != nil {
@@ -114,8 +128,10 @@ func (r *FollowerRepo) GetList(ctx context.Context, req entity.GetListFilter) (e
 
 	for rows.Next() {
 		var item entity.User
+		var pos cursor
 		err = rows.Scan(&item.ID, &item.FullName, &item.Email, &item.Username,
-			&item.UserType, &item.UserRole, &item.Status, &item.AvatarId, &item.Gender, &createdAt, &updatedAt)
+			&item.UserType, &item.UserRole, &item.Status, &item.AvatarId, &item.Gender, &createdAt, &updatedAt,
+			&pos.CreatedAt, &pos.Id)
 		if err != nil {
 			return response, err
 		}
@@ -124,9 +140,17 @@ func (r *FollowerRepo) GetList(ctx context.Context, req entity.GetListFilter) (e
 		item.UpdatedAt = updatedAt.Format(time.RFC3339)
 
 		response.Items = append(response.Items, item)
+		positions = append(positions, pos)
+	}
+
+	response.Items, response.NextCursor, response.PrevCursor = cursorPage(req, response.Items, positions)
+
+	if req.SkipCount {
+		return response, nil
 	}
 
-	countQuery, args, err := r.pg.Builder.Select("COUNT(1)").From("follower").Where(where).ToSql()
+	countQuery, args, err := r.pg.Builder.Select("COUNT(1)").
+		From("follower f").Join("users as u ON u.id=f.follower_id").Where(where).ToSql()
 	if err != nil {
 		return response, err
 	}
@@ -138,3 +162,81 @@ func (r *FollowerRepo) GetList(ctx context.Context, req entity.GetListFilter) (e
 
 	return response, nil
 }
+
+// FollowerCount returns the number of followers of the user.
+func (r *FollowerRepo) FollowerCount(ctx context.Context, req entity.Id) (int, error) {
+	var count int
+
+	qeury, args, err := r.pg.Builder.Select("COUNT(1)").From("follower").Where("following_id = ?", req.ID).ToSql()
+	if err != nil {
+		return count, err
+	}
+
+	err = r.pg.Pool.QueryRow(ctx, qeury, args...).Scan(&count)
+
+	return count, err
+}
+
+// FollowerIds returns a batch of follower ids of the user, pass the last id of a batch as After to get the next one.
+func (r *FollowerRepo) FollowerIds(ctx context.Context, req entity.FollowerIdsRequest) ([]string, error) {
+	response := []string{}
+
+	qeuryBuilder := r.pg.Builder.Select("follower_id").From("follower").
+		Where("following_id = ?", req.UserId).OrderBy("follower_id").Limit(uint64(req.Limit))
+	if req.After != "" {
+		qeuryBuilder = qeuryBuilder.Where("follower_id > ?", req.After)
+	}
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := r.pg.Pool.Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		if err = rows.Scan(&id); err != nil {
+			return response, err
+		}
+
+		response = append(response, id)
+	}
+
+	return response, rows.Err()
+}
+
+// FollowedAmong returns the ids among req.UserIds the user follows.
+func (r *FollowerRepo) FollowedAmong(ctx context.Context, req entity.FollowedAmongRequest) ([]string, error) {
+	response := []string{}
+	if req.UserId == "" || len(req.UserIds) == 0 {
+		return response, nil
+	}
+
+	qeury, args, err := r.pg.Builder.Select("following_id").From("follower").
+		Where("follower_id = ? AND following_id = ANY(?::uuid[])", req.UserId, req.UserIds).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := r.pg.Pool.Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		if err = rows.Scan(&id); err != nil {
+			return response, err
+		}
+
+		response = append(response, id)
+	}
+
+	return response, rows.Err()
+}
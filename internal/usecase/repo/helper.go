@@ -1,6 +1,8 @@
 package repo
 
 import (
+	"strings"
+
 	"github.com/Masterminds/squirrel"
 	"github.com/golanguzb70/udevslabs-twitter/internal/entity"
 )
@@ -25,6 +27,8 @@ func PrepareFilter(filters []entity.Filter) squirrel.And {
 			where = append(where, squirrel.LtOrEq{e.Column: e.Value})
 		case "search":
 			or = append(or, squirrel.ILike{e.Column: "%" + e.Value + "%"})
+		case "in":
+			where = append(where, squirrel.Eq{e.Column: strings.Split(e.Value, ",")})
 		}
 	}
 
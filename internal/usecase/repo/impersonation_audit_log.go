@@ -0,0 +1,95 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/golanguzb70/udevslabs-twitter/config"
+	"github.com/golanguzb70/udevslabs-twitter/internal/entity"
+	"github.com/golanguzb70/udevslabs-twitter/pkg/logger"
+	"github.com/golanguzb70/udevslabs-twitter/pkg/postgres"
+	"github.com/google/uuid"
+)
+
+type ImpersonationAuditLogRepo struct {
+	pg     *postgres.Postgres
+	config *config.Config
+	logger *logger.Logger
+}
+
+// New -.
+func NewImpersonationAuditLogRepo(pg *postgres.Postgres, config *config.Config, logger *logger.Logger) *ImpersonationAuditLogRepo {
+	return &ImpersonationAuditLogRepo{
+		pg:     pg,
+		config: config,
+		logger: logger,
+	}
+}
+
+func (r *ImpersonationAuditLogRepo) Create(ctx context.Context, req entity.ImpersonationAuditLog) (entity.ImpersonationAuditLog, error) {
+	req.ID = uuid.NewString()
+
+	qeury, args, err := r.pg.Builder.Insert("impersonation_audit_log").
+		Columns(`id, session_id, actor_id, user_id, method, path, status_code, ip_address, details`).
+		Values(req.ID, req.SessionID, req.ActorID, req.UserID, req.Method, req.Path, req.StatusCode, req.IPAddress, req.Details).ToSql()
+	if err != nil {
+		return entity.ImpersonationAuditLog{}, err
+	}
+
+	_, err = r.pg.Pool.Exec(ctx, qeury, args...)
+	if err != nil {
+		return entity.ImpersonationAuditLog{}, err
+	}
+
+	return req, nil
+}
+
+func (r *ImpersonationAuditLogRepo) GetList(ctx context.Context, req entity.GetListFilter) (entity.ImpersonationAuditLogList, error) {
+	var (
+		response = entity.ImpersonationAuditLogList{}
+	)
+
+	qeuryBuilder := r.pg.Builder.
+		Select(`id, session_id, actor_id, user_id, method, path, status_code, COALESCE(ip_address, ''), COALESCE(details, ''), created_at`).
+		From("impersonation_audit_log")
+
+	qeuryBuilder, where := PrepareGetListQuery(qeuryBuilder, req)
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := r.pg.Pool.Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			createdAt time.Time
+			item      entity.ImpersonationAuditLog
+		)
+		err = rows.Scan(&item.ID, &item.SessionID, &item.ActorID, &item.UserID, &item.Method,
+			&item.Path, &item.StatusCode, &item.IPAddress, &item.Details, &createdAt)
+		if err != nil {
+			return response, err
+		}
+
+		item.CreatedAt = createdAt.Format(time.RFC3339)
+
+		response.Items = append(response.Items, item)
+	}
+
+	countQuery, args, err := r.pg.Builder.Select("COUNT(1)").From("impersonation_audit_log").Where(where).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	err = r.pg.Pool.QueryRow(ctx, countQuery, args...).Scan(&response.Count)
+	if err != nil {
+		return response, err
+	}
+
+	return response, nil
+}
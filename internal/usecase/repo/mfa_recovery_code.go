@@ -0,0 +1,93 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/golanguzb70/udevslabs-twitter/config"
+	"github.com/golanguzb70/udevslabs-twitter/internal/entity"
+	"github.com/golanguzb70/udevslabs-twitter/pkg/logger"
+	"github.com/golanguzb70/udevslabs-twitter/pkg/postgres"
+	"github.com/google/uuid"
+)
+
+type MfaRecoveryCodeRepo struct {
+	pg     *postgres.Postgres
+	config *config.Config
+	logger *logger.Logger
+}
+
+// New -.
+func NewMfaRecoveryCodeRepo(pg *postgres.Postgres, config *config.Config, logger *logger.Logger) *MfaRecoveryCodeRepo {
+	return &MfaRecoveryCodeRepo{
+		pg:     pg,
+		config: config,
+		logger: logger,
+	}
+}
+
+// Replace removes every recovery code of the user and stores the new ones in a single transaction.
+func (r *MfaRecoveryCodeRepo) Replace(ctx context.Context, req entity.MfaRecoveryCodeReplaceRequest) error {
+	tx, err := r.pg.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query, args, err := r.pg.Builder.Delete("mfa_recovery_code").
+		Where(squirrel.Eq{"user_id": req.UserID}).ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("error while deleting mfa_recovery_code", err)
+		return err
+	}
+
+	if len(req.CodeHashes) > 0 {
+		insertQuery := r.pg.Builder.Insert("mfa_recovery_code").
+			Columns(`id, user_id, code_hash`)
+
+		for _, codeHash := range req.CodeHashes {
+			insertQuery = insertQuery.Values(uuid.NewString(), req.UserID, codeHash)
+		}
+
+		query, args, err = insertQuery.ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, query, args...)
+		if err != nil {
+			r.logger.Error("error while inserting mfa_recovery_code", err)
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *MfaRecoveryCodeRepo) UpdateField(ctx context.Context, req entity.UpdateFieldRequest) (entity.RowsEffected, error) {
+	mp := map[string]interface{}{}
+	response := entity.RowsEffected{}
+
+	for _, item := range req.Items {
+		mp[item.Column] = item.Value
+	}
+
+	qeury, args, err := r.pg.Builder.Update("mfa_recovery_code").SetMap(mp).Where(PrepareFilter(req.Filter)).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	n, err := r.pg.Pool.Exec(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+
+	response.RowsEffected = int(n.RowsAffected())
+
+	return response, nil
+}
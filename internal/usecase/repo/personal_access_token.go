@@ -0,0 +1,184 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/golanguzb70/udevslabs-twitter/config"
+	"github.com/golanguzb70/udevslabs-twitter/internal/entity"
+	"github.com/golanguzb70/udevslabs-twitter/pkg/logger"
+	"github.com/golanguzb70/udevslabs-twitter/pkg/postgres"
+	"github.com/google/uuid"
+)
+
+type PersonalAccessTokenRepo struct {
+	pg     *postgres.Postgres
+	config *config.Config
+	logger *logger.Logger
+}
+
+// New -.
+func NewPersonalAccessTokenRepo(pg *postgres.Postgres, config *config.Config, logger *logger.Logger) *PersonalAccessTokenRepo {
+	return &PersonalAccessTokenRepo{
+		pg:     pg,
+		config: config,
+		logger: logger,
+	}
+}
+
+func (r *PersonalAccessTokenRepo) Create(ctx context.Context, req entity.PersonalAccessToken) (entity.PersonalAccessToken, error) {
+	req.ID = uuid.NewString()
+	expireDate := sql.NullTime{}
+	expiresAt, err := time.Parse(time.RFC3339, req.ExpiresAt)
+	if err == nil {
+		expireDate.Time = expiresAt
+		expireDate.Valid = true
+	}
+
+	qeury, args, err := r.pg.Builder.Insert("personal_access_token").
+		Columns(`id, user_id, name, token_hash, scopes, expires_at`).
+		Values(req.ID, req.UserID, req.Name, req.TokenHash, req.Scopes, expireDate).ToSql()
+	if err != nil {
+		return entity.PersonalAccessToken{}, err
+	}
+
+	_, err = r.pg.Pool.Exec(ctx, qeury, args...)
+	if err != nil {
+		return entity.PersonalAccessToken{}, err
+	}
+
+	return req, nil
+}
+
+func (r *PersonalAccessTokenRepo) GetSingle(ctx context.Context, req entity.PersonalAccessTokenSingleRequest) (entity.PersonalAccessToken, error) {
+	qeuryBuilder := r.pg.Builder.
+		Select(`id, user_id, name, token_hash, scopes, expires_at, last_used_at, COALESCE(last_used_ip, ''), created_at, updated_at`).
+		From("personal_access_token")
+
+	switch {
+	case req.ID != "":
+		qeuryBuilder = qeuryBuilder.Where("id = ?", req.ID)
+	case req.TokenHash != "":
+		qeuryBuilder = qeuryBuilder.Where("token_hash = ?", req.TokenHash)
+	default:
+		return entity.PersonalAccessToken{}, fmt.Errorf("GetSingle - invalid request")
+	}
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return entity.PersonalAccessToken{}, err
+	}
+
+	return scanPersonalAccessToken(r.pg.Pool.QueryRow(ctx, qeury, args...))
+}
+
+func (r *PersonalAccessTokenRepo) GetList(ctx context.Context, req entity.GetListFilter) (entity.PersonalAccessTokenList, error) {
+	var (
+		response = entity.PersonalAccessTokenList{}
+	)
+
+	qeury, where := PrepareGetListQuery(r.pg.Builder.
+		Select(`id, user_id, name, token_hash, scopes, expires_at, last_used_at, COALESCE(last_used_ip, ''), created_at, updated_at`).
+		From("personal_access_token"), req)
+
+	qeuryStr, args, err := qeury.ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := r.pg.Pool.Query(ctx, qeuryStr, args...)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanPersonalAccessToken(rows)
+		if err != nil {
+			return response, err
+		}
+
+		response.Items = append(response.Items, item)
+	}
+
+	countQuery, args, err := r.pg.Builder.Select("COUNT(1)").From("personal_access_token").Where(where).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	err = r.pg.Pool.QueryRow(ctx, countQuery, args...).Scan(&response.Count)
+	if err != nil {
+		return response, err
+	}
+
+	return response, nil
+}
+
+func (r *PersonalAccessTokenRepo) Delete(ctx context.Context, req entity.Id) error {
+	qeury, args, err := r.pg.Builder.Delete("personal_access_token").Where("id = ?", req.ID).ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.pg.Pool.Exec(ctx, qeury, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *PersonalAccessTokenRepo) UpdateField(ctx context.Context, req entity.UpdateFieldRequest) (entity.RowsEffected, error) {
+	mp := map[string]interface{}{}
+	response := entity.RowsEffected{}
+
+	for _, item := range req.Items {
+		mp[item.Column] = item.Value
+	}
+
+	qeury, args, err := r.pg.Builder.Update("personal_access_token").SetMap(mp).Where(PrepareFilter(req.Filter)).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	n, err := r.pg.Pool.Exec(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+
+	response.RowsEffected = int(n.RowsAffected())
+
+	return response, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanPersonalAccessToken(row rowScanner) (entity.PersonalAccessToken, error) {
+	var (
+		item                  entity.PersonalAccessToken
+		expiresAt, lastUsedAt sql.NullTime
+		createdAt, updatedAt  time.Time
+	)
+
+	err := row.Scan(&item.ID, &item.UserID, &item.Name, &item.TokenHash, &item.Scopes,
+		&expiresAt, &lastUsedAt, &item.LastUsedIP, &createdAt, &updatedAt)
+	if err != nil {
+		return entity.PersonalAccessToken{}, err
+	}
+
+	item.CreatedAt = createdAt.Format(time.RFC3339)
+	item.UpdatedAt = updatedAt.Format(time.RFC3339)
+	if expiresAt.Valid {
+		item.ExpiresAt = expiresAt.Time.Format(time.RFC3339)
+	}
+
+	if lastUsedAt.Valid {
+		item.LastUsedAt = lastUsedAt.Time.Format(time.RFC3339)
+	}
+
+	return item, nil
+}
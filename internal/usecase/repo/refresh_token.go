@@ -0,0 +1,105 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/golanguzb70/udevslabs-twitter/config"
+	"github.com/golanguzb70/udevslabs-twitter/internal/entity"
+	"github.com/golanguzb70/udevslabs-twitter/pkg/logger"
+	"github.com/golanguzb70/udevslabs-twitter/pkg/postgres"
+	"github.com/google/uuid"
+)
+
+type RefreshTokenRepo struct {
+	pg     *postgres.Postgres
+	config *config.Config
+	logger *logger.Logger
+}
+
+// New -.
+func NewRefreshTokenRepo(pg *postgres.Postgres, config *config.Config, logger *logger.Logger) *RefreshTokenRepo {
+	return &RefreshTokenRepo{
+		pg:     pg,
+		config: config,
+		logger: logger,
+	}
+}
+
+func (r *RefreshTokenRepo) Create(ctx context.Context, req entity.RefreshToken) (entity.RefreshToken, error) {
+	req.ID = uuid.NewString()
+
+	qeury, args, err := r.pg.Builder.Insert("refresh_token").
+		Columns(`id, session_id, token_hash, is_used`).
+		Values(req.ID, req.SessionID, req.TokenHash, req.IsUsed).ToSql()
+	if err != nil {
+		return entity.RefreshToken{}, err
+	}
+
+	_, err = r.pg.Pool.Exec(ctx, qeury, args...)
+	if err != nil {
+		return entity.RefreshToken{}, err
+	}
+
+	return req, nil
+}
+
+func (r *RefreshTokenRepo) GetSingle(ctx context.Context, req entity.RefreshTokenSingleRequest) (entity.RefreshToken, error) {
+	response := entity.RefreshToken{}
+	var (
+		createdAt, updatedAt time.Time
+	)
+
+	qeuryBuilder := r.pg.Builder.
+		Select(`id, session_id, token_hash, is_used, created_at, updated_at`).
+		From("refresh_token")
+
+	switch {
+	case req.ID != "":
+		qeuryBuilder = qeuryBuilder.Where("id = ?", req.ID)
+	case req.TokenHash != "":
+		qeuryBuilder = qeuryBuilder.Where("token_hash = ?", req.TokenHash)
+	default:
+		return entity.RefreshToken{}, fmt.Errorf("GetSingle - invalid request")
+	}
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return entity.RefreshToken{}, err
+	}
+
+	err = r.pg.Pool.QueryRow(ctx, qeury, args...).
+		Scan(&response.ID, &response.SessionID, &response.TokenHash, &response.IsUsed, &createdAt, &updatedAt)
+	if err != nil {
+		return entity.RefreshToken{}, err
+	}
+
+	response.CreatedAt = createdAt.Format(time.RFC3339)
+	response.UpdatedAt = updatedAt.Format(time.RFC3339)
+
+	return response, nil
+}
+
+func (r *RefreshTokenRepo) UpdateField(ctx context.Context, req entity.UpdateFieldRequest) (entity.RowsEffected, error) {
+	mp := map[string]interface{}{}
+	response := entity.RowsEffected{}
+
+	for _, item := range req.Items {
+		mp[item.Column] = item.Value
+	}
+
+	qeury, args, err := r.pg.Builder.Update("refresh_token").SetMap(mp).Where(PrepareFilter(req.Filter)).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	n, err := r.pg.Pool.Exec(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+
+	response.RowsEffected = int(n.RowsAffected())
+
+	return response, nil
+}
@@ -3,27 +3,46 @@ package repo
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"fmt"
 	"time"
 
+	rediscache "github.com/golanguzb70/redis-cache"
 	"github.com/golanguzb70/udevslabs-twitter/config"
 	"github.com/golanguzb70/udevslabs-twitter/internal/entity"
 	"github.com/golanguzb70/udevslabs-twitter/pkg/logger"
+	"github.com/golanguzb70/udevslabs-twitter/pkg/lru"
 	"github.com/golanguzb70/udevslabs-twitter/pkg/postgres"
+	"github.com/golanguzb70/udevslabs-twitter/pkg/useragent"
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 )
 
+var sessionCacheRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "session_cache_requests_total",
+	Help: "Session cache lookups by tier and result, hit ratio is hit / (hit + miss) per tier.",
+}, []string{"tier", "result"})
+
+// SessionRepo caches sessions in two tiers: a short-lived in-process LRU and redis.
+// Every write goes to postgres first and then invalidates both tiers, the local tier
+// of other instances expires on its own after config.SessionCache.LocalTTL.
 type SessionRepo struct {
 	pg     *postgres.Postgres
 	config *config.Config
 	logger *logger.Logger
+	redis  rediscache.RedisCache
+	local  *lru.Cache[entity.Session]
 }
 
 // New -.
-func NewSessionRepo(pg *postgres.Postgres, config *config.Config, logger *logger.Logger) *SessionRepo {
+func NewSessionRepo(pg *postgres.Postgres, config *config.Config, logger *logger.Logger, redis rediscache.RedisCache) *SessionRepo {
 	return &SessionRepo{
 		pg:     pg,
 		config: config,
 		logger: logger,
+		redis:  redis,
+		local:  lru.New[entity.Session](config.SessionCache.LocalSize, config.SessionCache.LocalTTL),
 	}
 }
 
@@ -37,8 +56,9 @@ func (r *SessionRepo) Create(ctx context.Context, req entity.Session) (entity.Se
 	}
 
 	qeury, args, err := r.pg.Builder.Insert("session").
-		Columns(`id, user_id, ip_address, user_agent, is_active, expires_at, platform`).
-		Values(req.ID, req.UserID, req.IPAddress, req.UserAgent, req.IsActive, expireDate, req.Platform).ToSql()
+		Columns(`id, user_id, ip_address, user_agent, is_active, expires_at, platform, impersonator_id`).
+		Values(req.ID, req.UserID, req.IPAddress, req.UserAgent, req.IsActive, expireDate, req.Platform,
+			sql.NullString{String: req.ImpersonatorID, Valid: req.ImpersonatorID != ""}).ToSql()
 	if err != nil {
 		return entity.Session{}, err
 	}
@@ -52,6 +72,21 @@ func (r *SessionRepo) Create(ctx context.Context, req entity.Session) (entity.Se
 }
 
 func (r *SessionRepo) GetSingle(ctx context.Context, req entity.Id) (entity.Session, error) {
+	if session, ok := r.getCached(ctx, req.ID); ok {
+		return session, nil
+	}
+
+	response, err := r.getSingle(ctx, req)
+	if err != nil {
+		return entity.Session{}, err
+	}
+
+	r.setCached(ctx, response)
+
+	return response, nil
+}
+
+func (r *SessionRepo) getSingle(ctx context.Context, req entity.Id) (entity.Session, error) {
 	response := entity.Session{}
 	var (
 		createdAt, updatedAt    time.Time
@@ -59,7 +94,8 @@ func (r *SessionRepo) GetSingle(ctx context.Context, req entity.Id) (entity.Sess
 	)
 
 	qeuryBuilder := r.pg.Builder.
-		Select(`id, user_id, ip_address, user_agent, is_active, expires_at, last_active_at, platform, created_at, updated_at`).
+		Select(`id, user_id, ip_address, user_agent, is_active, expires_at, last_active_at, platform,
+			COALESCE(impersonator_id::text, ''), created_at, updated_at`).
 		From("session").Where("id = ?", req.ID)
 
 	qeury, args, err := qeuryBuilder.ToSql()
@@ -69,7 +105,7 @@ func (r *SessionRepo) GetSingle(ctx context.Context, req entity.Id) (entity.Sess
 
 	err = r.pg.Pool.QueryRow(ctx, qeury, args...).
 		Scan(&response.ID, &response.UserID, &response.IPAddress, &response.UserAgent,
-			&response.IsActive, &expiresAt, &lastActiveAt, &response.Platform, &createdAt, &updatedAt)
+			&response.IsActive, &expiresAt, &lastActiveAt, &response.Platform, &response.ImpersonatorID, &createdAt, &updatedAt)
 	if err != nil {
 		return entity.Session{}, err
 	}
@@ -84,19 +120,27 @@ func (r *SessionRepo) GetSingle(ctx context.Context, req entity.Id) (entity.Sess
 		response.LastActiveAt = lastActiveAt.Time.Format(time.RFC3339)
 	}
 
+	response.Device = parseDevice(response.UserAgent)
+
 	return response, nil
 }
 
 func (r *SessionRepo) GetList(ctx context.Context, req entity.GetListFilter) (entity.SessionList, error) {
 	var (
-		response = entity.SessionList{}
+		response  = entity.SessionList{}
+		positions []cursor
 	)
 
 	qeuryBuilder := r.pg.Builder.
-		Select(`id, user_id, ip_address, user_agent, is_active, expires_at, last_active_at, platform, created_at, updated_at`).
+		Select(`id, user_id, ip_address, user_agent, is_active, expires_at, last_active_at, platform,
+			COALESCE(impersonator_id::text, ''), created_at, updated_at`).
 		From("session")
 
-	qeuryBuilder, where := PrepareGetListQuery(qeuryBuilder, req)
+	qeuryBuilder, where, err := PrepareCursorQuery(qeuryBuilder, req, "created_at", "id")
+	if err != nil {
+		return response, err
+	}
+
 	qeury, args, err := qeuryBuilder.ToSql()
 	if err != nil {
 		return response, err
@@ -115,7 +159,7 @@ func (r *SessionRepo) GetList(ctx context.Context, req entity.GetListFilter) (en
 			item                    entity.Session
 		)
 		err = rows.Scan(&item.ID, &item.UserID, &item.IPAddress, &item.UserAgent,
-			&item.IsActive, &expiresAt, &lastActiveAt, &item.Platform, &createdAt, &updatedAt)
+			&item.IsActive, &expiresAt, &lastActiveAt, &item.Platform, &item.ImpersonatorID, &createdAt, &updatedAt)
 		if err != nil {
 			return response, err
 		}
@@ -130,7 +174,16 @@ func (r *SessionRepo) GetList(ctx context.Context, req entity.GetListFilter) (en
 			item.LastActiveAt = lastActiveAt.Time.Format(time.RFC3339)
 		}
 
+		item.Device = parseDevice(item.UserAgent)
+
 		response.Items = append(response.Items, item)
+		positions = append(positions, cursor{CreatedAt: createdAt, Id: item.ID})
+	}
+
+	response.Items, response.NextCursor, response.PrevCursor = cursorPage(req, response.Items, positions)
+
+	if req.SkipCount {
+		return response, nil
 	}
 
 	countQuery, args, err := r.pg.Builder.Select("COUNT(1)").From("session").Where(where).ToSql()
@@ -164,6 +217,8 @@ func (r *SessionRepo) Update(ctx context.Context, req entity.Session) (entity.Se
 		return entity.Session{}, err
 	}
 
+	r.invalidate(ctx, req.ID)
+
 	return req, nil
 }
 
@@ -178,6 +233,8 @@ func (r *SessionRepo) Delete(ctx context.Context, req entity.Id) error {
 		return err
 	}
 
+	r.invalidate(ctx, req.ID)
+
 	return nil
 }
 
@@ -189,17 +246,100 @@ func (r *SessionRepo) UpdateField(ctx context.Context, req entity.UpdateFieldReq
 		mp[item.Column] = item.Value
 	}
 
-	qeury, args, err := r.pg.Builder.Update("session").SetMap(mp).Where(PrepareFilter(req.Filter)).ToSql()
+	// filters may match many sessions, ids are returned so each of them can be invalidated
+	qeury, args, err := r.pg.Builder.Update("session").SetMap(mp).Where(PrepareFilter(req.Filter)).
+		Suffix("RETURNING id").ToSql()
 	if err != nil {
 		return response, err
 	}
 
-	n, err := r.pg.Pool.Exec(ctx, qeury, args...)
+	rows, err := r.pg.Pool.Query(ctx, qeury, args...)
 	if err != nil {
 		return response, err
 	}
+	defer rows.Close()
 
-	response.RowsEffected = int(n.RowsAffected())
+	for rows.Next() {
+		var id string
+		err = rows.Scan(&id)
+		if err != nil {
+			return response, err
+		}
 
-	return response, nil
+		r.invalidate(ctx, id)
+		response.RowsEffected++
+	}
+
+	return response, rows.Err()
+}
+
+func (r *SessionRepo) getCached(ctx context.Context, id string) (entity.Session, bool) {
+	if session, ok := r.local.Get(id); ok {
+		sessionCacheRequests.WithLabelValues("local", "hit").Inc()
+		return session, true
+	}
+	sessionCacheRequests.WithLabelValues("local", "miss").Inc()
+
+	value, err := r.redis.Get(ctx, sessionCacheKey(id))
+	if err != nil {
+		sessionCacheRequests.WithLabelValues("redis", "miss").Inc()
+		return entity.Session{}, false
+	}
+
+	var session entity.Session
+	if err = json.Unmarshal([]byte(value), &session); err != nil {
+		sessionCacheRequests.WithLabelValues("redis", "miss").Inc()
+		return entity.Session{}, false
+	}
+	sessionCacheRequests.WithLabelValues("redis", "hit").Inc()
+
+	r.local.Set(id, session)
+
+	return session, true
+}
+
+func (r *SessionRepo) setCached(ctx context.Context, session entity.Session) {
+	r.local.Set(session.ID, session)
+
+	value, err := json.Marshal(session)
+	if err != nil {
+		return
+	}
+
+	err = r.redis.Set(ctx, sessionCacheKey(session.ID), string(value), int(r.config.SessionCache.RedisTTL.Seconds()))
+	if err != nil {
+		r.logger.Error(err, "SessionRepo - setCached - redis.Set")
+	}
+}
+
+// Invalidate drops sessions deleted outside of SessionRepo from both cache tiers.
+func (r *SessionRepo) Invalidate(ctx context.Context, ids []string) {
+	for _, id := range ids {
+		r.invalidate(ctx, id)
+	}
+}
+
+func (r *SessionRepo) invalidate(ctx context.Context, id string) {
+	r.local.Del(id)
+
+	err := r.redis.Del(ctx, sessionCacheKey(id))
+	if err != nil {
+		r.logger.Error(err, "SessionRepo - invalidate - redis.Del")
+	}
+}
+
+func parseDevice(userAgent string) entity.Device {
+	device := useragent.Parse(userAgent)
+
+	return entity.Device{
+		Name:    device.String(),
+		Browser: device.Browser,
+		OS:      device.OS,
+		App:     device.App,
+		Mobile:  device.Mobile,
+	}
+}
+
+func sessionCacheKey(id string) string {
+	return fmt.Sprintf("session-%s", id)
 }
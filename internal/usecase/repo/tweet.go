@@ -2,17 +2,50 @@ package repo
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"time"
 
+	"github.com/Masterminds/squirrel"
 	"github.com/golanguzb70/udevslabs-twitter/config"
 	"github.com/golanguzb70/udevslabs-twitter/internal/entity"
 	"github.com/golanguzb70/udevslabs-twitter/pkg/logger"
+	"github.com/golanguzb70/udevslabs-twitter/pkg/mention"
 	"github.com/golanguzb70/udevslabs-twitter/pkg/postgres"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
 )
 
+// _tweetOwnerJSON is the public profile of a tweet owner selected from users u.
+const _tweetOwnerJSON = `json_build_object('id', u.id, 'full_name', u.full_name, 'username', u.username,
+	'avatar_id', u.avatar_id, 'gender', u.gender, 'user_type', u.user_type, 'status', u.status)`
+
+// _tweetRelationColumns are the thread, repost and counter columns shared by GetSingle and GetList,
+// the counters are kept by triggers.
+var _tweetRelationColumns = `COALESCE(tweet.parent_id::text, ''), tweet.conversation_id,
+	COALESCE(tweet.quote_of_id::text, ''), COALESCE(tweet.retweet_of_id::text, ''),
+	tweet.reply_count, tweet.retweet_count, tweet.quote_count, tweet.like_count, tweet.publish_at, tweet.edited_at, tweet.revision_count,
+	json_build_object('mentions', ` + tweetMentions("tweet") + `) AS entities,
+	(
+		SELECT json_build_object('id', o.id, 'content', o.content, 'status', o.status,
+			'conversation_id', o.conversation_id, 'created_at', o.created_at, 'updated_at', o.updated_at,
+			'reply_count', o.reply_count, 'retweet_count', o.retweet_count, 'quote_count', o.quote_count,
+			'like_count', o.like_count, 'edited_at', o.edited_at, 'revision_count', o.revision_count,
+			'entities', json_build_object('mentions', ` + tweetMentions("o") + `),
+			'owner', (SELECT ` + _tweetOwnerJSON + ` FROM users u WHERE u.id = o.owner_id),
+			'attachments', (SELECT COALESCE(json_agg(row_to_json(ta)), '[]'::json) FROM tweet_attachment ta WHERE ta.tweet_id = o.id))
+		FROM tweet o
+		WHERE o.id = COALESCE(tweet.retweet_of_id, tweet.quote_of_id)
+	) AS original`
+
+// tweetMentions returns the mentions of the tweet with the alias as a json array in content order.
+func tweetMentions(alias string) string {
+	return fmt.Sprintf(`(SELECT COALESCE(json_agg(json_build_object('user_id', tm.user_id, 'username', tm.username,
+		'start', tm.start_offset, 'end', tm.end_offset) ORDER BY tm.start_offset), '[]'::json)
+		FROM tweet_mention tm WHERE tm.tweet_id = %s.id)`, alias)
+}
+
 type TweetRepo struct {
 	pg     *postgres.Postgres
 	config *config.Config
@@ -30,15 +63,41 @@ func NewTweetRepo(pg *postgres.Postgres, config *config.Config, logger *logger.L
 
 func (r *TweetRepo) Create(ctx context.Context, req entity.Tweet) (entity.Tweet, error) {
 	req.Id = uuid.NewString()
+	if req.ConversationId == "" {
+		req.ConversationId = req.Id
+	}
+
+	publishAt := sql.NullTime{}
+	if req.Status == "scheduled" {
+		publishAt.Time, publishAt.Valid = parsePublishAt(req.PublishAt)
+	}
 
 	qeury, args, err := r.pg.Builder.Insert("tweet").
-		Columns(`id, owner_id, content, tags, status`).
-		Values(req.Id, req.Owner.ID, req.Content, req.Tags, req.Status).ToSql()
+		Columns(`id, owner_id, content, tags, status, parent_id, conversation_id, quote_of_id, publish_at`).
+		Values(req.Id, req.Owner.ID, req.Content, req.Tags, req.Status,
+			sql.NullString{String: req.ParentId, Valid: req.ParentId != ""}, req.ConversationId,
+			sql.NullString{String: req.QuoteOfId, Valid: req.QuoteOfId != ""}, publishAt).ToSql()
 	if err != nil {
 		return entity.Tweet{}, err
 	}
 
-	_, err = r.pg.Pool.Exec(ctx, qeury, args...)
+	tx, err := r.pg.Pool.Begin(ctx)
+	if err != nil {
+		return entity.Tweet{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, qeury, args...)
+	if err != nil {
+		return entity.Tweet{}, err
+	}
+
+	req.Entities.Mentions, err = r.syncMentions(ctx, tx, req.Id, req.Content)
+	if err != nil {
+		return entity.Tweet{}, err
+	}
+
+	err = tx.Commit(ctx)
 	if err != nil {
 		return entity.Tweet{}, err
 	}
@@ -46,6 +105,90 @@ func (r *TweetRepo) Create(ctx context.Context, req entity.Tweet) (entity.Tweet,
 	return req, nil
 }
 
+// syncMentions replaces the mentions of the tweet with the @usernames in content that belong to a user
+// and returns them, unknown usernames are left as plain text.
+func (r *TweetRepo) syncMentions(ctx context.Context, tx pgx.Tx, tweetID, content string) ([]entity.Mention, error) {
+	response := []entity.Mention{}
+
+	qeury, args, err := r.pg.Builder.Delete("tweet_mention").Where("tweet_id = ?", tweetID).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	_, err = tx.Exec(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+
+	mentions := mention.Parse(content)
+	if len(mentions) == 0 {
+		return response, nil
+	}
+
+	usernames := make([]string, 0, len(mentions))
+	for _, m := range mentions {
+		usernames = append(usernames, m.Username)
+	}
+
+	qeury, args, err = r.pg.Builder.Select("id, username").From("users").
+		Where("username = ANY(?::text[])", usernames).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := tx.Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+
+	userIDs := make(map[string]string, len(usernames))
+	for rows.Next() {
+		var id, username string
+		if err = rows.Scan(&id, &username); err != nil {
+			rows.Close()
+			return response, err
+		}
+
+		userIDs[username] = id
+	}
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return response, err
+	}
+
+	insertBuilder := r.pg.Builder.Insert("tweet_mention").
+		Columns("id, tweet_id, user_id, username, start_offset, end_offset")
+
+	for _, m := range mentions {
+		userID, ok := userIDs[m.Username]
+		if !ok {
+			continue
+		}
+
+		insertBuilder = insertBuilder.Values(uuid.NewString(), tweetID, userID, m.Username, m.Start, m.End)
+		response = append(response, entity.Mention{
+			UserId:   userID,
+			Username: m.Username,
+			Start:    m.Start,
+			End:      m.End,
+		})
+	}
+
+	if len(response) == 0 {
+		return response, nil
+	}
+
+	qeury, args, err = insertBuilder.ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	_, err = tx.Exec(ctx, qeury, args...)
+
+	return response, err
+}
+
 func (r *TweetRepo) GetSingle(ctx context.Context, req entity.Id) (entity.Tweet, error) {
 	response := entity.Tweet{}
 	var (
@@ -53,7 +196,7 @@ func (r *TweetRepo) GetSingle(ctx context.Context, req entity.Id) (entity.Tweet,
 	)
 
 	qeuryBuilder := r.pg.Builder.
-		Select(`id, owner_id, content, tags, status, created_at, updated_at`).
+		Select(`id, owner_id, content, tags, status, ` + _tweetRelationColumns + `, created_at, updated_at`).
 		From("tweet")
 
 	switch {
@@ -69,18 +212,47 @@ func (r *TweetRepo) GetSingle(ctx context.Context, req entity.Id) (entity.Tweet,
 	}
 
 	tags := []byte{}
+	original := []byte{}
+	entities := []byte{}
+	publishAt, editedAt := sql.NullTime{}, sql.NullTime{}
 
 	err = r.pg.Pool.QueryRow(ctx, qeury, args...).
-		Scan(&response.Id, &response.Owner.ID, &response.Content, &tags, &response.Status, &createdAt, &updatedAt)
+		Scan(&response.Id, &response.Owner.ID, &response.Content, &tags, &response.Status,
+			&response.ParentId, &response.ConversationId, &response.QuoteOfId, &response.RetweetOfId,
+			&response.ReplyCount, &response.RetweetCount, &response.QuoteCount, &response.LikeCount, &publishAt,
+			&editedAt, &response.RevisionCount, &entities, &original, &createdAt, &updatedAt)
 	if err != nil {
 		return entity.Tweet{}, err
 	}
 
-	err = json.Unmarshal(tags, &response.Tags)
+	if publishAt.Valid {
+		response.PublishAt = publishAt.Time.Format(time.RFC3339)
+	}
+
+	if editedAt.Valid {
+		response.EditedAt = editedAt.Time.Format(time.RFC3339)
+	}
+
+	err = json.Unmarshal(entities, &response.Entities)
 	if err != nil {
 		return entity.Tweet{}, err
 	}
 
+	if len(original) != 0 {
+		err = json.Unmarshal(original, &response.Original)
+		if err != nil {
+			return entity.Tweet{}, err
+		}
+	}
+
+	// retweets have no tags
+	if len(tags) != 0 {
+		err = json.Unmarshal(tags, &response.Tags)
+		if err != nil {
+			return entity.Tweet{}, err
+		}
+	}
+
 	response.CreatedAt = createdAt.Format(time.RFC3339)
 	response.UpdatedAt = updatedAt.Format(time.RFC3339)
 
@@ -88,25 +260,68 @@ func (r *TweetRepo) GetSingle(ctx context.Context, req entity.Id) (entity.Tweet,
 }
 
 func (r *TweetRepo) GetList(ctx context.Context, req entity.GetListFilter) (entity.TweetList, error) {
+	return r.getList(ctx, req, "", "tweet.created_at", "tweet.id")
+}
+
+// GetBookmarks lists bookmarked tweets like GetList, filters and order refer to the bookmark as b.
+func (r *TweetRepo) GetBookmarks(ctx context.Context, req entity.GetListFilter) (entity.TweetList, error) {
+	return r.getList(ctx, req, "bookmark b ON b.tweet_id = tweet.id", "b.created_at", "b.id")
+}
+
+// GetMentions lists tweets like GetList ordered by the publish time, filters refer to the mentioned users
+// as m.user_id. A tweet mentioning a user several times is listed once.
+func (r *TweetRepo) GetMentions(ctx context.Context, req entity.GetListFilter) (entity.TweetList, error) {
+	return r.getList(ctx, req, "(SELECT DISTINCT tweet_id, user_id FROM tweet_mention) m ON m.tweet_id = tweet.id",
+		"COALESCE(tweet.publish_at, tweet.created_at)", "tweet.id")
+}
+
+// GetScheduled lists scheduled tweets like GetList ordered by publish_at.
+func (r *TweetRepo) GetScheduled(ctx context.Context, req entity.GetListFilter) (entity.TweetList, error) {
+	req.Filters = append(req.Filters, entity.Filter{
+		Column: "tweet.status",
+		Type:   "eq",
+		Value:  "scheduled",
+	})
+
+	return r.getList(ctx, req, "", "tweet.publish_at", "tweet.id")
+}
+
+// getList lists tweets paged by the createdAt and id columns.
+func (r *TweetRepo) getList(ctx context.Context, req entity.GetListFilter, join, createdAtColumn, idColumn string) (entity.TweetList, error) {
 	var (
 		response             = entity.TweetList{}
 		createdAt, updatedAt time.Time
+		positions            []cursor
 	)
 
 	qeuryBuilder := r.pg.Builder.
-		Select(`id, owner_id, content, status, created_at, updated_at, 
+		Select(`tweet.id, tweet.owner_id, tweet.content, tweet.status, ` + _tweetRelationColumns + `, tweet.created_at, tweet.updated_at,
 				(SELECT COALESCE(json_agg(row_to_json(ta)), '[]'::json) 
 				 FROM tweet_attachment ta 
 				 WHERE ta.tweet_id = tweet.id) AS attachments, 
 				 (
-					SELECT row_to_json(u)
+					SELECT ` + _tweetOwnerJSON + `
 					FROM users u
 					WHERE u.id = tweet.owner_id
 					LIMIT 1
-				) AS user`).
+				) AS user, ` + createdAtColumn + `, ` + idColumn).
 		From("tweet")
 
-	qeuryBuilder, where := PrepareGetListQuery(qeuryBuilder, req)
+	countBuilder := r.pg.Builder.Select("COUNT(1)").From("tweet")
+	if join != "" {
+		qeuryBuilder = qeuryBuilder.Join(join)
+		countBuilder = countBuilder.Join(join)
+	}
+
+	qeuryBuilder, where, err := PrepareCursorQuery(qeuryBuilder, req, createdAtColumn, idColumn)
+	if err != nil {
+		return response, err
+	}
+
+	// tweets of deactivated accounts are hidden until the account is reactivated or purged
+	hideDeactivated := squirrel.Expr("tweet.owner_id NOT IN (SELECT id FROM users WHERE status = 'deactivated')")
+	qeuryBuilder = qeuryBuilder.Where(hideDeactivated)
+	where = append(where, hideDeactivated)
 
 	qeury, args, err := qeuryBuilder.ToSql()
 	if err != nil {
@@ -123,11 +338,37 @@ func (r *TweetRepo) GetList(ctx context.Context, req entity.GetListFilter) (enti
 		var item entity.Tweet
 		var attachmentsJSON []byte
 		var userJson []byte
-		err = rows.Scan(&item.Id, &item.Owner.ID, &item.Content, &item.Status, &createdAt, &updatedAt, &attachmentsJSON, &userJson)
+		var originalJSON []byte
+		var entitiesJSON []byte
+		var publishAt, editedAt sql.NullTime
+		var pos cursor
+		err = rows.Scan(&item.Id, &item.Owner.ID, &item.Content, &item.Status, &item.ParentId, &item.ConversationId,
+			&item.QuoteOfId, &item.RetweetOfId, &item.ReplyCount, &item.RetweetCount, &item.QuoteCount, &item.LikeCount, &publishAt,
+			&editedAt, &item.RevisionCount, &entitiesJSON, &originalJSON, &createdAt, &updatedAt, &attachmentsJSON, &userJson, &pos.CreatedAt, &pos.Id)
+		if err != nil {
+			return response, err
+		}
+
+		if publishAt.Valid {
+			item.PublishAt = publishAt.Time.Format(time.RFC3339)
+		}
+
+		if editedAt.Valid {
+			item.EditedAt = editedAt.Time.Format(time.RFC3339)
+		}
+
+		err = json.Unmarshal(entitiesJSON, &item.Entities)
 		if err != nil {
 			return response, err
 		}
 
+		if len(originalJSON) != 0 {
+			err = json.Unmarshal(originalJSON, &item.Original)
+			if err != nil {
+				return response, err
+			}
+		}
+
 		item.CreatedAt = createdAt.Format(time.RFC3339)
 		item.UpdatedAt = updatedAt.Format(time.RFC3339)
 
@@ -142,9 +383,16 @@ func (r *TweetRepo) GetList(ctx context.Context, req entity.GetListFilter) (enti
 		}
 
 		response.Items = append(response.Items, item)
+		positions = append(positions, pos)
+	}
+
+	response.Items, response.NextCursor, response.PrevCursor = cursorPage(req, response.Items, positions)
+
+	if req.SkipCount {
+		return response, nil
 	}
 
-	countQuery, args, err := r.pg.Builder.Select("COUNT(1)").From("tweet").Where(where).ToSql()
+	countQuery, args, err := countBuilder.Where(where).ToSql()
 	if err != nil {
 		return response, err
 	}
@@ -157,24 +405,135 @@ func (r *TweetRepo) GetList(ctx context.Context, req entity.GetListFilter) (enti
 	return response, nil
 }
 
+// Update edits the tweet. A published tweet keeps its prior version as a revision when its content or
+// attachments change, and can only be edited within Tweet.EditWindow of being published.
 func (r *TweetRepo) Update(ctx context.Context, req entity.Tweet) (entity.Tweet, error) {
+	tx, err := r.pg.Pool.Begin(ctx)
+	if err != nil {
+		return entity.Tweet{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	var (
+		current         entity.Tweet
+		tags            []byte
+		attachmentsJSON []byte
+		publishedAt     time.Time
+	)
+
+	qeury, args, err := r.pg.Builder.
+		Select(`content, tags, status, COALESCE(retweet_of_id::text, ''), revision_count, COALESCE(publish_at, created_at),
+			(SELECT COALESCE(json_agg(row_to_json(ta)), '[]'::json) FROM tweet_attachment ta WHERE ta.tweet_id = tweet.id)`).
+		From("tweet").Where("id = ?", req.Id).Suffix("FOR UPDATE").ToSql()
+	if err != nil {
+		return entity.Tweet{}, err
+	}
+
+	err = tx.QueryRow(ctx, qeury, args...).Scan(&current.Content, &tags, &current.Status, &current.RetweetOfId,
+		&current.RevisionCount, &publishedAt, &attachmentsJSON)
+	if err != nil {
+		return entity.Tweet{}, err
+	}
+
+	// retweets have no content of their own
+	if current.RetweetOfId != "" {
+		return entity.Tweet{}, fmt.Errorf("%sretweets can't be edited", "BAD_REQUEST")
+	}
+
 	mp := map[string]interface{}{
-		"content":    req.Content,
-		"status":     req.Status,
+		"content": req.Content,
+		"status":  req.Status,
+		// publishing starts the edit window, a scheduled tweet published early is published now
+		// and leaving the scheduled status cancels the schedule
+		"publish_at": squirrel.Expr(`CASE
+			WHEN status = 'scheduled' AND ?::text = 'published' THEN now()
+			WHEN status = 'scheduled' AND ?::text <> 'scheduled' THEN NULL
+			WHEN status = 'draft' AND ?::text = 'published' THEN COALESCE(publish_at, now())
+			ELSE publish_at END`, req.Status, req.Status, req.Status),
 		"updated_at": "now()",
 	}
 
-	qeury, args, err := r.pg.Builder.Update("tweet").SetMap(mp).Where("id = ?", req.Id).ToSql()
+	if current.Status == "published" {
+		window := r.config.Tweet.EditWindow
+		if window > 0 && time.Since(publishedAt) > window {
+			return entity.Tweet{}, fmt.Errorf("%spublished tweets can only be edited within %s", "BAD_REQUEST", window)
+		}
+
+		err = json.Unmarshal(attachmentsJSON, &current.Attachments)
+		if err != nil {
+			return entity.Tweet{}, err
+		}
+
+		if current.Content != req.Content || attachmentsChanged(current.Attachments, req.Attachments) {
+			qeury, args, err = r.pg.Builder.Insert("tweet_revision").
+				Columns(`id, tweet_id, revision, content, tags, attachments, editor_id`).
+				Values(uuid.NewString(), req.Id, current.RevisionCount+1, current.Content, tags, attachmentsJSON,
+					sql.NullString{String: req.EditorId, Valid: req.EditorId != ""}).ToSql()
+			if err != nil {
+				return entity.Tweet{}, err
+			}
+
+			_, err = tx.Exec(ctx, qeury, args...)
+			if err != nil {
+				return entity.Tweet{}, err
+			}
+
+			mp["edited_at"] = "now()"
+			mp["revision_count"] = current.RevisionCount + 1
+		}
+	}
+
+	qeury, args, err = r.pg.Builder.Update("tweet").SetMap(mp).Where("id = ?", req.Id).ToSql()
 	if err != nil {
 		return entity.Tweet{}, err
 	}
 
-	_, err = r.pg.Pool.Exec(ctx, qeury, args...)
+	_, err = tx.Exec(ctx, qeury, args...)
 	if err != nil {
 		return entity.Tweet{}, err
 	}
 
-	return req, nil
+	if current.Content != req.Content {
+		_, err = r.syncMentions(ctx, tx, req.Id, req.Content)
+		if err != nil {
+			return entity.Tweet{}, err
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return entity.Tweet{}, err
+	}
+
+	response, err := r.GetSingle(ctx, entity.Id{ID: req.Id})
+	response.Published = current.Status != "published" && req.Status == "published"
+
+	return response, err
+}
+
+// attachmentsChanged reports whether the attachments requested by an update differ from the current ones,
+// attachments without an id are added and current ones left out are removed.
+func attachmentsChanged(current, requested []entity.Attachment) bool {
+	kept := make(map[string]bool, len(requested))
+	for _, attachment := range requested {
+		if attachment.Id == "" {
+			return true
+		}
+
+		kept[attachment.Id] = true
+	}
+
+	if len(kept) != len(current) {
+		return true
+	}
+
+	for _, attachment := range current {
+		if !kept[attachment.Id] {
+			return true
+		}
+	}
+
+	return false
 }
 
 func (r *TweetRepo) Delete(ctx context.Context, req entity.Id) error {
@@ -213,3 +572,216 @@ func (r *TweetRepo) UpdateField(ctx context.Context, req entity.UpdateFieldReque
 
 	return response, nil
 }
+
+// Retweet reposts the tweet for the user. Retweeting the same tweet again returns the existing retweet,
+// Published tells the two apart.
+func (r *TweetRepo) Retweet(ctx context.Context, req entity.RetweetRequest) (entity.Tweet, error) {
+	id := uuid.NewString()
+
+	qeury, args, err := r.pg.Builder.Insert("tweet").
+		Columns(`id, owner_id, content, status, conversation_id, retweet_of_id`).
+		Values(id, req.UserId, "", "published", id, req.TweetId).
+		Suffix("ON CONFLICT (owner_id, retweet_of_id) WHERE retweet_of_id IS NOT NULL DO NOTHING").ToSql()
+	if err != nil {
+		return entity.Tweet{}, err
+	}
+
+	result, err := r.pg.Pool.Exec(ctx, qeury, args...)
+	if err != nil {
+		return entity.Tweet{}, err
+	}
+
+	qeury, args, err = r.pg.Builder.Select("id").From("tweet").
+		Where("owner_id = ? AND retweet_of_id = ?", req.UserId, req.TweetId).ToSql()
+	if err != nil {
+		return entity.Tweet{}, err
+	}
+
+	err = r.pg.Pool.QueryRow(ctx, qeury, args...).Scan(&id)
+	if err != nil {
+		return entity.Tweet{}, err
+	}
+
+	response, err := r.GetSingle(ctx, entity.Id{ID: id})
+	response.Published = result.RowsAffected() == 1
+
+	return response, err
+}
+
+// Unretweet removes the retweet of the user, it is a no-op if there is none.
+func (r *TweetRepo) Unretweet(ctx context.Context, req entity.RetweetRequest) error {
+	qeury, args, err := r.pg.Builder.Delete("tweet").
+		Where("owner_id = ? AND retweet_of_id = ?", req.UserId, req.TweetId).ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.pg.Pool.Exec(ctx, qeury, args...)
+
+	return err
+}
+
+// TimelineEntries returns the newest published tweets of the home timeline of req.UserId or of req.OwnerIds.
+func (r *TweetRepo) TimelineEntries(ctx context.Context, req entity.TimelineRequest) ([]entity.TimelineEntry, error) {
+	response := []entity.TimelineEntry{}
+
+	// scheduled tweets are placed at the time they were published
+	qeuryBuilder := r.pg.Builder.Select("id, COALESCE(publish_at, created_at)").From("tweet").
+		Where("status = 'published'").
+		Where("owner_id NOT IN (SELECT id FROM users WHERE status = 'deactivated')").
+		OrderBy("COALESCE(publish_at, created_at) DESC").Limit(uint64(req.Limit))
+
+	switch {
+	case req.UserId != "":
+		qeuryBuilder = qeuryBuilder.Where("(owner_id = ? OR owner_id IN (SELECT following_id FROM follower WHERE follower_id = ?))",
+			req.UserId, req.UserId)
+	case len(req.OwnerIds) != 0:
+		qeuryBuilder = qeuryBuilder.Where("owner_id = ANY(?::uuid[])", req.OwnerIds)
+	default:
+		return response, nil
+	}
+
+	if req.Before > 0 {
+		qeuryBuilder = qeuryBuilder.Where("COALESCE(publish_at, created_at) < ?", time.UnixMilli(req.Before).UTC())
+	}
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := r.pg.Pool.Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			item      entity.TimelineEntry
+			createdAt time.Time
+		)
+		if err = rows.Scan(&item.TweetId, &createdAt); err != nil {
+			return response, err
+		}
+
+		item.Score = createdAt.UnixMilli()
+		response = append(response, item)
+	}
+
+	return response, rows.Err()
+}
+
+// Schedule sets the publish time of a draft or scheduled tweet.
+func (r *TweetRepo) Schedule(ctx context.Context, req entity.ScheduleTweetRequest) (entity.Tweet, error) {
+	publishAt, ok := parsePublishAt(req.PublishAt)
+	if !ok {
+		return entity.Tweet{}, fmt.Errorf("%sinvalid publish_at", "BAD_REQUEST")
+	}
+
+	qeury, args, err := r.pg.Builder.Update("tweet").
+		SetMap(map[string]interface{}{
+			"status":     "scheduled",
+			"publish_at": publishAt,
+			"updated_at": "now()",
+		}).
+		Where("id = ? AND status IN ('draft', 'scheduled') AND retweet_of_id IS NULL", req.Id).ToSql()
+	if err != nil {
+		return entity.Tweet{}, err
+	}
+
+	result, err := r.pg.Pool.Exec(ctx, qeury, args...)
+	if err != nil {
+		return entity.Tweet{}, err
+	}
+
+	if result.RowsAffected() == 0 {
+		return entity.Tweet{}, fmt.Errorf("%sonly drafts and scheduled tweets can be scheduled", "BAD_REQUEST")
+	}
+
+	return r.GetSingle(ctx, entity.Id{ID: req.Id})
+}
+
+// Unschedule turns a scheduled tweet back into a draft.
+func (r *TweetRepo) Unschedule(ctx context.Context, req entity.Id) (entity.Tweet, error) {
+	qeury, args, err := r.pg.Builder.Update("tweet").
+		SetMap(map[string]interface{}{
+			"status":     "draft",
+			"publish_at": nil,
+			"updated_at": "now()",
+		}).
+		Where("id = ? AND status = 'scheduled'", req.ID).ToSql()
+	if err != nil {
+		return entity.Tweet{}, err
+	}
+
+	result, err := r.pg.Pool.Exec(ctx, qeury, args...)
+	if err != nil {
+		return entity.Tweet{}, err
+	}
+
+	if result.RowsAffected() == 0 {
+		return entity.Tweet{}, fmt.Errorf("%sthe tweet isn't scheduled", "BAD_REQUEST")
+	}
+
+	return r.GetSingle(ctx, entity.Id{ID: req.ID})
+}
+
+// PublishScheduled publishes up to req.Limit due scheduled tweets and returns them. Rows locked by another
+// instance are skipped and the status is checked again after locking, so every tweet is published once.
+func (r *TweetRepo) PublishScheduled(ctx context.Context, req entity.PublishScheduledRequest) ([]entity.Tweet, error) {
+	response := []entity.Tweet{}
+
+	if req.Limit <= 0 {
+		req.Limit = 100
+	}
+
+	qeury, args, err := r.pg.Builder.Update("tweet").
+		SetMap(map[string]interface{}{
+			"status":     "published",
+			"updated_at": "now()",
+		}).
+		Where(`id IN (
+			SELECT id FROM tweet
+			WHERE status = 'scheduled' AND publish_at <= now()
+			ORDER BY publish_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		) AND status = 'scheduled'`, req.Limit).
+		Suffix("RETURNING id, owner_id, publish_at").ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := r.pg.Pool.Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			item      entity.Tweet
+			publishAt time.Time
+		)
+		if err = rows.Scan(&item.Id, &item.Owner.ID, &publishAt); err != nil {
+			return response, err
+		}
+
+		item.Status = "published"
+		item.PublishAt = publishAt.Format(time.RFC3339)
+		response = append(response, item)
+	}
+
+	return response, rows.Err()
+}
+
+// parsePublishAt parses an RFC3339 publish time, publish_at is stored in UTC like created_at.
+func parsePublishAt(value string) (time.Time, bool) {
+	publishAt, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return publishAt.UTC(), true
+}
@@ -0,0 +1,164 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/golanguzb70/udevslabs-twitter/config"
+	"github.com/golanguzb70/udevslabs-twitter/internal/entity"
+	"github.com/golanguzb70/udevslabs-twitter/pkg/logger"
+	"github.com/golanguzb70/udevslabs-twitter/pkg/postgres"
+	"github.com/google/uuid"
+)
+
+type TweetLikeRepo struct {
+	pg     *postgres.Postgres
+	config *config.Config
+	logger *logger.Logger
+}
+
+// New -.
+func NewTweetLikeRepo(pg *postgres.Postgres, config *config.Config, logger *logger.Logger) *TweetLikeRepo {
+	return &TweetLikeRepo{
+		pg:     pg,
+		config: config,
+		logger: logger,
+	}
+}
+
+// Like is idempotent, liking a tweet twice keeps a single like.
+func (r *TweetLikeRepo) Like(ctx context.Context, req entity.TweetLike) (entity.TweetLike, error) {
+	qeury, args, err := r.pg.Builder.Insert("tweet_like").
+		Columns(`id, tweet_id, user_id`).
+		Values(uuid.NewString(), req.TweetId, req.UserId).
+		Suffix("ON CONFLICT (tweet_id, user_id) DO NOTHING").ToSql()
+	if err != nil {
+		return req, err
+	}
+
+	_, err = r.pg.Pool.Exec(ctx, qeury, args...)
+	if err != nil {
+		return req, err
+	}
+
+	req.Liked = true
+
+	return req, r.likeCount(ctx, &req)
+}
+
+// Unlike is idempotent, it succeeds if the user didn't like the tweet.
+func (r *TweetLikeRepo) Unlike(ctx context.Context, req entity.TweetLike) (entity.TweetLike, error) {
+	qeury, args, err := r.pg.Builder.Delete("tweet_like").
+		Where("tweet_id = ? AND user_id = ?", req.TweetId, req.UserId).ToSql()
+	if err != nil {
+		return req, err
+	}
+
+	_, err = r.pg.Pool.Exec(ctx, qeury, args...)
+	if err != nil {
+		return req, err
+	}
+
+	req.Liked = false
+
+	return req, r.likeCount(ctx, &req)
+}
+
+func (r *TweetLikeRepo) likeCount(ctx context.Context, req *entity.TweetLike) error {
+	qeury, args, err := r.pg.Builder.Select("like_count").From("tweet").Where("id = ?", req.TweetId).ToSql()
+	if err != nil {
+		return err
+	}
+
+	return r.pg.Pool.QueryRow(ctx, qeury, args...).Scan(&req.LikeCount)
+}
+
+// GetList returns the users who liked the tweet of the tweet_id filter, latest likes first.
+func (r *TweetLikeRepo) GetList(ctx context.Context, req entity.GetListFilter) (entity.UserList, error) {
+	var (
+		response             = entity.UserList{}
+		createdAt, updatedAt time.Time
+	)
+
+	// deactivated accounts are hidden like in follower lists
+	req.Filters = append(req.Filters, entity.Filter{
+		Column: "u.status",
+		Type:   "neq",
+		Value:  "deactivated",
+	})
+
+	qeuryBuilder := r.pg.Builder.
+		Select(`u.id, u.full_name, u.username, u.user_type, u.status, u.avatar_id, u.gender, u.created_at, u.updated_at`).
+		From("tweet_like l").Join("users as u ON u.id=l.user_id")
+
+	qeuryBuilder, where := PrepareGetListQuery(qeuryBuilder, req)
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := r.pg.Pool.Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item entity.User
+		err = rows.Scan(&item.ID, &item.FullName, &item.Username, &item.UserType,
+			&item.Status, &item.AvatarId, &item.Gender, &createdAt, &updatedAt)
+		if err != nil {
+			return response, err
+		}
+
+		item.CreatedAt = createdAt.Format(time.RFC3339)
+		item.UpdatedAt = updatedAt.Format(time.RFC3339)
+
+		response.Items = append(response.Items, item)
+	}
+
+	countQuery, args, err := r.pg.Builder.Select("COUNT(1)").
+		From("tweet_like l").Join("users as u ON u.id=l.user_id").Where(where).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	err = r.pg.Pool.QueryRow(ctx, countQuery, args...).Scan(&response.Count)
+	if err != nil {
+		return response, err
+	}
+
+	return response, nil
+}
+
+// LikedTweets returns the ids of the tweets among req.TweetIds the user liked.
+func (r *TweetLikeRepo) LikedTweets(ctx context.Context, req entity.UserTweetsRequest) (map[string]bool, error) {
+	response := make(map[string]bool)
+	if req.UserId == "" || len(req.TweetIds) == 0 {
+		return response, nil
+	}
+
+	qeury, args, err := r.pg.Builder.Select("tweet_id").From("tweet_like").
+		Where("user_id = ? AND tweet_id = ANY(?::uuid[])", req.UserId, req.TweetIds).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := r.pg.Pool.Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tweetID string
+		if err = rows.Scan(&tweetID); err != nil {
+			return response, err
+		}
+
+		response[tweetID] = true
+	}
+
+	return response, rows.Err()
+}
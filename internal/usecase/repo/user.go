@@ -2,9 +2,11 @@ package repo
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"time"
 
+	"github.com/Masterminds/squirrel"
 	"github.com/golanguzb70/udevslabs-twitter/config"
 	"github.com/golanguzb70/udevslabs-twitter/internal/entity"
 	"github.com/golanguzb70/udevslabs-twitter/pkg/logger"
@@ -48,11 +50,13 @@ func (r *UserRepo) Create(ctx context.Context, req entity.User) (entity.User, er
 func (r *UserRepo) GetSingle(ctx context.Context, req entity.UserSingleRequest) (entity.User, error) {
 	response := entity.User{}
 	var (
-		createdAt, updatedAt time.Time
+		createdAt, updatedAt       time.Time
+		deactivatedAt, deleteAfter sql.NullTime
 	)
 
 	qeuryBuilder := r.pg.Builder.
-		Select(`id, full_name, email, username, password, user_type, user_role, status, avatar_id, gender, created_at, updated_at`).
+		Select(`id, full_name, email, username, password, user_type, user_role, status, avatar_id, gender,
+			totp_enabled, COALESCE(totp_secret, ''), deactivated_at, delete_after, created_at, updated_at`).
 		From("users")
 
 	switch {
@@ -73,25 +77,35 @@ func (r *UserRepo) GetSingle(ctx context.Context, req entity.UserSingleRequest)
 
 	err = r.pg.Pool.QueryRow(ctx, qeury, args...).
 		Scan(&response.ID, &response.FullName, &response.Email, &response.Username, &response.Password,
-			&response.UserType, &response.UserRole, &response.Status, &response.AvatarId, &response.Gender, &createdAt, &updatedAt)
+			&response.UserType, &response.UserRole, &response.Status, &response.AvatarId, &response.Gender,
+			&response.TotpEnabled, &response.TotpSecret, &deactivatedAt, &deleteAfter, &createdAt, &updatedAt)
 	if err != nil {
 		return entity.User{}, err
 	}
 
 	response.CreatedAt = createdAt.Format(time.RFC3339)
 	response.UpdatedAt = updatedAt.Format(time.RFC3339)
+	if deactivatedAt.Valid {
+		response.DeactivatedAt = deactivatedAt.Time.Format(time.RFC3339)
+	}
+
+	if deleteAfter.Valid {
+		response.DeleteAfter = deleteAfter.Time.Format(time.RFC3339)
+	}
 
 	return response, nil
 }
 
 func (r *UserRepo) GetList(ctx context.Context, req entity.GetListFilter) (entity.UserList, error) {
 	var (
-		response             = entity.UserList{}
-		createdAt, updatedAt time.Time
+		response                   = entity.UserList{}
+		createdAt, updatedAt       time.Time
+		deactivatedAt, deleteAfter sql.NullTime
 	)
 
 	qeuryBuilder := r.pg.Builder.
-		Select(`id, full_name, email, username, password, user_type, user_role, status, avatar_id, gender, created_at, updated_at`).
+		Select(`id, full_name, email, username, password, user_type, user_role, status, avatar_id, gender, totp_enabled,
+			deactivated_at, delete_after, created_at, updated_at`).
 		From("users")
 
 	qeuryBuilder, where := PrepareGetListQuery(qeuryBuilder, req)
@@ -110,13 +124,21 @@ func (r *UserRepo) GetList(ctx context.Context, req entity.GetListFilter) (entit
 	for rows.Next() {
 		var item entity.User
 		err = rows.Scan(&item.ID, &item.FullName, &item.Email, &item.Username, &item.Password,
-			&item.UserType, &item.UserRole, &item.Status, &item.AvatarId, &item.Gender, &createdAt, &updatedAt)
+			&item.UserType, &item.UserRole, &item.Status, &item.AvatarId, &item.Gender, &item.TotpEnabled,
+			&deactivatedAt, &deleteAfter, &createdAt, &updatedAt)
 		if err != nil {
 			return response, err
 		}
 
 		item.CreatedAt = createdAt.Format(time.RFC3339)
 		item.UpdatedAt = updatedAt.Format(time.RFC3339)
+		if deactivatedAt.Valid {
+			item.DeactivatedAt = deactivatedAt.Time.Format(time.RFC3339)
+		}
+
+		if deleteAfter.Valid {
+			item.DeleteAfter = deleteAfter.Time.Format(time.RFC3339)
+		}
 
 		response.Items = append(response.Items, item)
 	}
@@ -199,3 +221,126 @@ func (r *UserRepo) UpdateField(ctx context.Context, req entity.UpdateFieldReques
 
 	return response, nil
 }
+
+// Purge deletes users with everything that cascades from them and returns the attachment file
+// paths of their tweets and the ids of their sessions, so the caller can remove the files and
+// invalidate the cached sessions. Rows are locked with SKIP LOCKED,
+// instances running the purge at the same time never pick the same user.
+func (r *UserRepo) Purge(ctx context.Context, req entity.UserPurgeRequest) (entity.UserPurgeResult, error) {
+	response := entity.UserPurgeResult{}
+
+	tx, err := r.pg.Pool.Begin(ctx)
+	if err != nil {
+		return response, err
+	}
+	defer tx.Rollback(ctx)
+
+	qeuryBuilder := r.pg.Builder.Select("id").From("users").Suffix("FOR UPDATE SKIP LOCKED")
+
+	if req.ID != "" {
+		qeuryBuilder = qeuryBuilder.Where("id = ?", req.ID)
+	} else {
+		if req.Limit <= 0 {
+			req.Limit = 100
+		}
+
+		qeuryBuilder = qeuryBuilder.
+			Where("status = 'deactivated' AND delete_after <= now()").
+			OrderBy("delete_after").
+			Limit(uint64(req.Limit))
+	}
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := tx.Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+
+	for rows.Next() {
+		var id string
+		if err = rows.Scan(&id); err != nil {
+			rows.Close()
+			return response, err
+		}
+
+		response.UserIDs = append(response.UserIDs, id)
+	}
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return response, err
+	}
+
+	if len(response.UserIDs) == 0 {
+		return response, nil
+	}
+
+	qeury, args, err = r.pg.Builder.Select("ta.filepath").
+		From("tweet_attachment ta").Join("tweet t ON t.id = ta.tweet_id").
+		Where(squirrel.Eq{"t.owner_id": response.UserIDs}).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err = tx.Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+
+	for rows.Next() {
+		var path string
+		if err = rows.Scan(&path); err != nil {
+			rows.Close()
+			return response, err
+		}
+
+		response.FilePaths = append(response.FilePaths, path)
+	}
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return response, err
+	}
+
+	qeury, args, err = r.pg.Builder.Delete("session").Where(squirrel.Eq{"user_id": response.UserIDs}).
+		Suffix("RETURNING id").ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err = tx.Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+
+	for rows.Next() {
+		var id string
+		if err = rows.Scan(&id); err != nil {
+			rows.Close()
+			return response, err
+		}
+
+		response.SessionIDs = append(response.SessionIDs, id)
+	}
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return response, err
+	}
+
+	qeury, args, err = r.pg.Builder.Delete("users").Where(squirrel.Eq{"id": response.UserIDs}).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	_, err = tx.Exec(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+
+	return response, tx.Commit(ctx)
+}
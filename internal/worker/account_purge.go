@@ -0,0 +1,96 @@
+// Package worker implements background jobs started by app.Run.
+package worker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golanguzb70/udevslabs-twitter/config"
+	"github.com/golanguzb70/udevslabs-twitter/internal/entity"
+	"github.com/golanguzb70/udevslabs-twitter/internal/usecase"
+	"github.com/golanguzb70/udevslabs-twitter/pkg/etc"
+	"github.com/golanguzb70/udevslabs-twitter/pkg/logger"
+)
+
+// AccountPurger permanently deletes deactivated accounts whose grace period is over.
+type AccountPurger struct {
+	useCase *usecase.UseCase
+	config  *config.Config
+	logger  *logger.Logger
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New -.
+func NewAccountPurger(useCase *usecase.UseCase, config *config.Config, logger *logger.Logger) *AccountPurger {
+	return &AccountPurger{
+		useCase: useCase,
+		config:  config,
+		logger:  logger,
+		stop:    make(chan struct{}),
+	}
+}
+
+// Start runs the purge every AccountDeletion.PurgeInterval until Stop is called.
+func (p *AccountPurger) Start() {
+	p.wg.Add(1)
+
+	go func() {
+		defer p.wg.Done()
+
+		ticker := time.NewTicker(p.config.AccountDeletion.PurgeInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-p.stop:
+				return
+			case <-ticker.C:
+				p.Purge(context.Background())
+			}
+		}
+	}()
+}
+
+// Stop waits for a running purge to finish.
+func (p *AccountPurger) Stop() {
+	close(p.stop)
+	p.wg.Wait()
+}
+
+// Purge deletes expired accounts batch by batch and removes their attachment files.
+func (p *AccountPurger) Purge(ctx context.Context) {
+	for {
+		result, err := p.useCase.UserRepo.Purge(ctx, entity.UserPurgeRequest{
+			Limit: p.config.AccountDeletion.BatchSize,
+		})
+		if err != nil {
+			p.logger.Error(fmt.Errorf("worker - AccountPurger - Purge: %w", err))
+			return
+		}
+
+		p.useCase.SessionRepo.Invalidate(ctx, result.SessionIDs)
+		RemoveAttachmentFiles(p.config, p.logger, result.FilePaths)
+
+		if len(result.UserIDs) > 0 {
+			p.logger.Info(fmt.Sprintf("worker - AccountPurger - purged %d accounts", len(result.UserIDs)))
+		}
+
+		if len(result.UserIDs) < p.config.AccountDeletion.BatchSize {
+			return
+		}
+	}
+}
+
+// RemoveAttachmentFiles removes files of deleted attachments, failures are only logged
+// since the rows are already gone.
+func RemoveAttachmentFiles(config *config.Config, logger *logger.Logger, paths []string) {
+	for _, path := range paths {
+		if err := etc.RemoveFile(config.Storage.AttachmentsDir, path); err != nil {
+			logger.Error(fmt.Errorf("worker - RemoveAttachmentFiles: %w", err))
+		}
+	}
+}
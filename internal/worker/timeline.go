@@ -0,0 +1,141 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/golanguzb70/udevslabs-twitter/config"
+	"github.com/golanguzb70/udevslabs-twitter/internal/entity"
+	"github.com/golanguzb70/udevslabs-twitter/internal/usecase"
+	"github.com/golanguzb70/udevslabs-twitter/pkg/logger"
+	"github.com/redis/go-redis/v9"
+)
+
+// FanOutOnReadKey is the set of accounts with too many followers to fan out on write, their tweets are merged
+// into home timelines on read. An account stays in the set once added, so its tweets don't disappear from
+// the timelines they weren't pushed to.
+const FanOutOnReadKey = "timeline-fan-out-on-read"
+
+// followers are read and pushed to in batches of this size
+const _fanOutBatchSize = 1000
+
+// timelinePush adds a tweet to a cached home timeline and trims it to the max size.
+// Timelines that aren't cached are left alone, they are rebuilt from postgres on the next read.
+var timelinePush = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return 0
+end
+
+redis.call('ZADD', KEYS[1], 'NX', ARGV[1], ARGV[2])
+redis.call('ZREMRANGEBYRANK', KEYS[1], 0, -tonumber(ARGV[3]) - 1)
+
+return 1
+`)
+
+// HomeTimelineKey is the sorted set of tweet ids on the home timeline of the user,
+// scored by the publish time in unix milliseconds.
+func HomeTimelineKey(userID string) string {
+	return "timeline-home-" + userID
+}
+
+// TimelineFanOut pushes published tweets to the cached home timelines of their owners and followers.
+type TimelineFanOut struct {
+	useCase *usecase.UseCase
+	redis   *redis.Client
+	config  *config.Config
+	logger  *logger.Logger
+}
+
+// New -.
+func NewTimelineFanOut(useCase *usecase.UseCase, redisClient *redis.Client, config *config.Config, logger *logger.Logger) *TimelineFanOut {
+	return &TimelineFanOut{
+		useCase: useCase,
+		redis:   redisClient,
+		config:  config,
+		logger:  logger,
+	}
+}
+
+// Publish fans the tweet out in the background, it is scored by its publish time or else its creation time.
+func (f *TimelineFanOut) Publish(tweet entity.Tweet) {
+	entry := entity.TimelineEntry{
+		TweetId: tweet.Id,
+		Score:   time.Now().UnixMilli(),
+	}
+
+	// tweets published earlier keep their place on the timeline
+	for _, at := range []string{tweet.PublishAt, tweet.CreatedAt} {
+		if publishedAt, err := time.Parse(time.RFC3339, at); err == nil {
+			entry.Score = publishedAt.UnixMilli()
+			break
+		}
+	}
+
+	ownerID := tweet.Owner.ID
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		if err := f.FanOut(ctx, ownerID, entry); err != nil {
+			f.logger.Error(fmt.Errorf("worker - TimelineFanOut - FanOut: %w", err))
+		}
+	}()
+}
+
+// FanOut pushes the entry to the timelines of the owner and its followers.
+// Followers of accounts above Timeline.FanOutMaxFollowers get the tweets merged in on read instead.
+func (f *TimelineFanOut) FanOut(ctx context.Context, ownerID string, entry entity.TimelineEntry) error {
+	err := f.Push(ctx, []string{ownerID}, entry)
+	if err != nil {
+		return err
+	}
+
+	count, err := f.useCase.FollowerRepo.FollowerCount(ctx, entity.Id{ID: ownerID})
+	if err != nil {
+		return err
+	}
+
+	if count > f.config.Timeline.FanOutMaxFollowers {
+		return f.redis.SAdd(ctx, FanOutOnReadKey, ownerID).Err()
+	}
+
+	isFanOutOnRead, err := f.redis.SIsMember(ctx, FanOutOnReadKey, ownerID).Result()
+	if err != nil || isFanOutOnRead {
+		return err
+	}
+
+	req := entity.FollowerIdsRequest{
+		UserId: ownerID,
+		Limit:  _fanOutBatchSize,
+	}
+
+	for {
+		followerIDs, err := f.useCase.FollowerRepo.FollowerIds(ctx, req)
+		if err != nil || len(followerIDs) == 0 {
+			return err
+		}
+
+		err = f.Push(ctx, followerIDs, entry)
+		if err != nil {
+			return err
+		}
+
+		req.After = followerIDs[len(followerIDs)-1]
+	}
+}
+
+// Push adds the entries to the cached home timelines of the users.
+func (f *TimelineFanOut) Push(ctx context.Context, userIDs []string, entries ...entity.TimelineEntry) error {
+	_, err := f.redis.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, userID := range userIDs {
+			for _, entry := range entries {
+				timelinePush.Eval(ctx, pipe, []string{HomeTimelineKey(userID)}, entry.Score, entry.TweetId, f.config.Timeline.MaxSize)
+			}
+		}
+		return nil
+	})
+
+	return err
+}
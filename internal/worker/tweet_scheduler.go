@@ -0,0 +1,88 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golanguzb70/udevslabs-twitter/config"
+	"github.com/golanguzb70/udevslabs-twitter/internal/entity"
+	"github.com/golanguzb70/udevslabs-twitter/internal/usecase"
+	"github.com/golanguzb70/udevslabs-twitter/pkg/logger"
+)
+
+// TweetScheduler publishes scheduled tweets once their publish_at has passed. Every instance runs it,
+// PublishScheduled makes sure each tweet is published by only one of them.
+type TweetScheduler struct {
+	useCase *usecase.UseCase
+	config  *config.Config
+	logger  *logger.Logger
+	fanOut  *TimelineFanOut
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New -.
+func NewTweetScheduler(useCase *usecase.UseCase, config *config.Config, logger *logger.Logger, fanOut *TimelineFanOut) *TweetScheduler {
+	return &TweetScheduler{
+		useCase: useCase,
+		config:  config,
+		logger:  logger,
+		fanOut:  fanOut,
+		stop:    make(chan struct{}),
+	}
+}
+
+// Start publishes due tweets every Tweet.ScheduleInterval until Stop is called.
+func (s *TweetScheduler) Start() {
+	s.wg.Add(1)
+
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(s.config.Tweet.ScheduleInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+				s.Publish(context.Background())
+			}
+		}
+	}()
+}
+
+// Stop waits for a running publish to finish.
+func (s *TweetScheduler) Stop() {
+	close(s.stop)
+	s.wg.Wait()
+}
+
+// Publish publishes due tweets batch by batch and pushes them to the home timelines.
+func (s *TweetScheduler) Publish(ctx context.Context) {
+	for {
+		tweets, err := s.useCase.TweetRepo.PublishScheduled(ctx, entity.PublishScheduledRequest{
+			Limit: s.config.Tweet.ScheduleBatchSize,
+		})
+		if err != nil {
+			s.logger.Error(fmt.Errorf("worker - TweetScheduler - Publish: %w", err))
+			return
+		}
+
+		for _, tweet := range tweets {
+			s.fanOut.Publish(tweet)
+		}
+
+		if len(tweets) > 0 {
+			s.logger.Info(fmt.Sprintf("worker - TweetScheduler - published %d tweets", len(tweets)))
+		}
+
+		if len(tweets) < s.config.Tweet.ScheduleBatchSize {
+			return
+		}
+	}
+}
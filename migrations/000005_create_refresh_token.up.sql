@@ -0,0 +1,10 @@
+CREATE TABLE refresh_token (
+  id uuid PRIMARY KEY,
+  session_id uuid NOT NULL REFERENCES session(id) ON DELETE CASCADE,
+  token_hash varchar(64) UNIQUE NOT NULL,
+  is_used bool NOT NULL DEFAULT false,
+  created_at timestamp NOT NULL DEFAULT now(),
+  updated_at timestamp NOT NULL DEFAULT now()
+);
+
+CREATE INDEX ON "refresh_token" ("session_id");
@@ -0,0 +1,4 @@
+DROP TABLE mfa_recovery_code;
+
+ALTER TABLE users DROP COLUMN totp_enabled;
+ALTER TABLE users DROP COLUMN totp_secret;
@@ -0,0 +1,13 @@
+ALTER TABLE users ADD COLUMN totp_secret varchar(64);
+ALTER TABLE users ADD COLUMN totp_enabled bool NOT NULL DEFAULT false;
+
+CREATE TABLE mfa_recovery_code (
+  id uuid PRIMARY KEY,
+  user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  code_hash varchar(64) NOT NULL,
+  is_used bool NOT NULL DEFAULT false,
+  created_at timestamp NOT NULL DEFAULT now(),
+  updated_at timestamp NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX ON "mfa_recovery_code" ("user_id", "code_hash");
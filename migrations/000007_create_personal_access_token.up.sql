@@ -0,0 +1,14 @@
+CREATE TABLE personal_access_token (
+  id uuid PRIMARY KEY,
+  user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  name varchar(100) NOT NULL,
+  token_hash varchar(64) UNIQUE NOT NULL,
+  scopes text[] NOT NULL DEFAULT '{}',
+  expires_at timestamp,
+  last_used_at timestamp,
+  last_used_ip varchar(64),
+  created_at timestamp NOT NULL DEFAULT now(),
+  updated_at timestamp NOT NULL DEFAULT now()
+);
+
+CREATE INDEX ON "personal_access_token" ("user_id");
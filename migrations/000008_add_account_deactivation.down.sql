@@ -0,0 +1,5 @@
+ALTER TABLE users DROP COLUMN delete_after;
+ALTER TABLE users DROP COLUMN deactivated_at;
+
+-- postgres can't drop a value from an enum, 'deactivated' stays in user_status
+UPDATE users SET status = 'active' WHERE status = 'deactivated';
@@ -0,0 +1,6 @@
+ALTER TYPE user_status ADD VALUE IF NOT EXISTS 'deactivated';
+
+ALTER TABLE users ADD COLUMN deactivated_at timestamp;
+ALTER TABLE users ADD COLUMN delete_after timestamp;
+
+CREATE INDEX ON "users" ("delete_after") WHERE delete_after IS NOT NULL;
@@ -0,0 +1 @@
+DROP TABLE casbin_rule;
@@ -0,0 +1,12 @@
+CREATE TABLE casbin_rule (
+  id serial PRIMARY KEY,
+  ptype varchar(10) NOT NULL,
+  v0 varchar(256) NOT NULL DEFAULT '',
+  v1 varchar(256) NOT NULL DEFAULT '',
+  v2 varchar(256) NOT NULL DEFAULT '',
+  v3 varchar(256) NOT NULL DEFAULT '',
+  v4 varchar(256) NOT NULL DEFAULT '',
+  v5 varchar(256) NOT NULL DEFAULT ''
+);
+
+CREATE UNIQUE INDEX ON "casbin_rule" ("ptype", "v0", "v1", "v2", "v3", "v4", "v5");
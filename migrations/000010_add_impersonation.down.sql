@@ -0,0 +1,5 @@
+DELETE FROM casbin_rule WHERE ptype = 'p' AND v0 = 'admin' AND v1 = '/v1/impersonation/*';
+
+DROP TABLE impersonation_audit_log;
+
+ALTER TABLE session DROP COLUMN impersonator_id;
@@ -0,0 +1,24 @@
+ALTER TABLE session ADD COLUMN impersonator_id uuid REFERENCES users(id) ON DELETE CASCADE;
+
+-- ids are not foreign keys, the log has to outlive the sessions and users it mentions
+CREATE TABLE impersonation_audit_log (
+  id uuid PRIMARY KEY,
+  session_id uuid NOT NULL,
+  actor_id uuid NOT NULL,
+  user_id uuid NOT NULL,
+  method varchar(10) NOT NULL,
+  path text NOT NULL,
+  status_code int NOT NULL,
+  ip_address varchar(64),
+  details text,
+  created_at timestamp NOT NULL DEFAULT now()
+);
+
+CREATE INDEX ON "impersonation_audit_log" ("session_id");
+CREATE INDEX ON "impersonation_audit_log" ("actor_id");
+CREATE INDEX ON "impersonation_audit_log" ("user_id");
+
+INSERT INTO casbin_rule (ptype, v0, v1, v2)
+SELECT 'p', 'admin', '/v1/impersonation/*', 'GET|POST'
+WHERE EXISTS (SELECT 1 FROM casbin_rule)
+ON CONFLICT DO NOTHING;
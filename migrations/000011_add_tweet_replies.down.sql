@@ -0,0 +1,3 @@
+ALTER TABLE tweet
+  DROP COLUMN parent_id,
+  DROP COLUMN conversation_id;
@@ -0,0 +1,11 @@
+ALTER TABLE tweet
+  ADD COLUMN parent_id uuid REFERENCES tweet(id) ON DELETE SET NULL,
+  ADD COLUMN conversation_id uuid;
+
+-- every existing tweet starts its own conversation
+UPDATE tweet SET conversation_id = id;
+
+ALTER TABLE tweet ALTER COLUMN conversation_id SET NOT NULL;
+
+CREATE INDEX ON "tweet" ("parent_id", "created_at");
+CREATE INDEX ON "tweet" ("conversation_id", "created_at");
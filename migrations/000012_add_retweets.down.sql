@@ -0,0 +1,5 @@
+DELETE FROM tweet WHERE retweet_of_id IS NOT NULL;
+
+ALTER TABLE tweet
+  DROP COLUMN retweet_of_id,
+  DROP COLUMN quote_of_id;
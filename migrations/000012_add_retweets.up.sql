@@ -0,0 +1,9 @@
+-- a retweet is a tweet without content pointing at the original, a quote tweet has content of its own
+ALTER TABLE tweet
+  ADD COLUMN retweet_of_id uuid REFERENCES tweet(id) ON DELETE CASCADE,
+  ADD COLUMN quote_of_id uuid REFERENCES tweet(id) ON DELETE SET NULL;
+
+-- a user retweets a tweet at most once
+CREATE UNIQUE INDEX tweet_retweet_unique ON "tweet" ("owner_id", "retweet_of_id") WHERE retweet_of_id IS NOT NULL;
+CREATE INDEX ON "tweet" ("retweet_of_id");
+CREATE INDEX ON "tweet" ("quote_of_id");
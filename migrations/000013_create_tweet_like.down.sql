@@ -0,0 +1,5 @@
+DROP TABLE tweet_like;
+
+DROP FUNCTION tweet_like_count();
+
+ALTER TABLE tweet DROP COLUMN like_count;
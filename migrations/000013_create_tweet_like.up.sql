@@ -0,0 +1,29 @@
+CREATE TABLE tweet_like (
+  id uuid PRIMARY KEY,
+  tweet_id uuid NOT NULL REFERENCES tweet(id) ON DELETE CASCADE,
+  user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  created_at timestamp NOT NULL DEFAULT now(),
+  updated_at timestamp NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX ON "tweet_like" ("tweet_id", "user_id");
+CREATE INDEX ON "tweet_like" ("user_id", "created_at");
+
+ALTER TABLE tweet ADD COLUMN like_count int NOT NULL DEFAULT 0;
+
+-- the counter is kept by a trigger so likes removed by cascades (deleted users) are counted too
+CREATE FUNCTION tweet_like_count() RETURNS trigger AS $$
+BEGIN
+  IF TG_OP = 'INSERT' THEN
+    UPDATE tweet SET like_count = like_count + 1 WHERE id = NEW.tweet_id;
+    RETURN NEW;
+  END IF;
+
+  UPDATE tweet SET like_count = like_count - 1 WHERE id = OLD.tweet_id;
+  RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER tweet_like_count
+AFTER INSERT OR DELETE ON tweet_like
+FOR EACH ROW EXECUTE FUNCTION tweet_like_count();
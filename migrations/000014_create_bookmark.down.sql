@@ -0,0 +1,5 @@
+DELETE FROM casbin_rule WHERE ptype = 'p' AND v0 = 'user' AND v1 = '/v1/bookmark/*';
+
+DROP TABLE bookmark;
+
+DROP TABLE bookmark_folder;
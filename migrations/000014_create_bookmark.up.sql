@@ -0,0 +1,27 @@
+CREATE TABLE bookmark_folder (
+  id uuid PRIMARY KEY,
+  user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  name varchar(50) NOT NULL,
+  created_at timestamp NOT NULL DEFAULT now(),
+  updated_at timestamp NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX ON "bookmark_folder" ("user_id", "name");
+
+CREATE TABLE bookmark (
+  id uuid PRIMARY KEY,
+  user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  tweet_id uuid NOT NULL REFERENCES tweet(id) ON DELETE CASCADE,
+  -- bookmarks of a deleted folder stay in the unsorted list
+  folder_id uuid REFERENCES bookmark_folder(id) ON DELETE SET NULL,
+  created_at timestamp NOT NULL DEFAULT now(),
+  updated_at timestamp NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX ON "bookmark" ("user_id", "tweet_id");
+CREATE INDEX ON "bookmark" ("user_id", "created_at");
+
+INSERT INTO casbin_rule (ptype, v0, v1, v2)
+SELECT 'p', 'user', '/v1/bookmark/*', 'GET|POST|PUT|DELETE'
+WHERE EXISTS (SELECT 1 FROM casbin_rule)
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,5 @@
+DELETE FROM casbin_rule WHERE ptype = 'p' AND v0 = 'user' AND v1 = '/v1/timeline/*';
+
+DROP INDEX tweet_owner_created_idx;
+
+DROP INDEX follower_following_idx;
@@ -0,0 +1,10 @@
+-- followers of an account are read in batches when its tweets are fanned out to home timelines
+CREATE INDEX follower_following_idx ON "follower" ("following_id", "follower_id");
+
+-- timelines are rebuilt from the recent tweets of the followed accounts
+CREATE INDEX tweet_owner_created_idx ON "tweet" ("owner_id", "created_at");
+
+INSERT INTO casbin_rule (ptype, v0, v1, v2)
+SELECT 'p', 'user', '/v1/timeline/*', 'GET'
+WHERE EXISTS (SELECT 1 FROM casbin_rule)
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,20 @@
+UPDATE tweet SET status = 'draft' WHERE status = 'scheduled';
+
+DROP INDEX tweet_publish_at_idx;
+
+ALTER TABLE tweet DROP COLUMN publish_at;
+
+-- enum values can't be dropped, the type is recreated without it
+ALTER TYPE tweet_status RENAME TO tweet_status_old;
+
+CREATE TYPE tweet_status AS ENUM (
+    'draft',
+    'published'
+);
+
+ALTER TABLE tweet
+  ALTER COLUMN status DROP DEFAULT,
+  ALTER COLUMN status TYPE tweet_status USING status::text::tweet_status,
+  ALTER COLUMN status SET DEFAULT 'draft';
+
+DROP TYPE tweet_status_old;
@@ -0,0 +1,6 @@
+-- the new value can't be used in the same transaction, so the index doesn't filter on it
+ALTER TYPE tweet_status ADD VALUE IF NOT EXISTS 'scheduled';
+
+ALTER TABLE tweet ADD COLUMN publish_at timestamp;
+
+CREATE INDEX tweet_publish_at_idx ON "tweet" ("publish_at") WHERE publish_at IS NOT NULL;
@@ -0,0 +1,5 @@
+ALTER TABLE tweet
+  DROP COLUMN revision_count,
+  DROP COLUMN edited_at;
+
+DROP TABLE tweet_revision;
@@ -0,0 +1,17 @@
+-- prior versions of published tweets, revision 1 is the original
+CREATE TABLE tweet_revision (
+  id uuid PRIMARY KEY,
+  tweet_id uuid NOT NULL REFERENCES tweet(id) ON DELETE CASCADE,
+  revision int NOT NULL,
+  content text NOT NULL,
+  tags json,
+  attachments json NOT NULL DEFAULT '[]',
+  editor_id uuid REFERENCES users(id) ON DELETE SET NULL,
+  created_at timestamp NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX ON "tweet_revision" ("tweet_id", "revision");
+
+ALTER TABLE tweet
+  ADD COLUMN edited_at timestamp,
+  ADD COLUMN revision_count int NOT NULL DEFAULT 0;
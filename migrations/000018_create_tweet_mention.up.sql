@@ -0,0 +1,14 @@
+-- @username mentions of users in tweet content, one row per occurrence.
+-- username is kept as written so the offsets still match the content after a rename
+CREATE TABLE tweet_mention (
+  id uuid PRIMARY KEY,
+  tweet_id uuid NOT NULL REFERENCES tweet(id) ON DELETE CASCADE,
+  user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  username varchar(50) NOT NULL,
+  start_offset int NOT NULL,
+  end_offset int NOT NULL,
+  created_at timestamp NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX ON "tweet_mention" ("tweet_id", "start_offset");
+CREATE INDEX ON "tweet_mention" ("user_id", "tweet_id");
@@ -0,0 +1,10 @@
+DELETE FROM casbin_rule WHERE ptype = 'p' AND (
+  (v0 = 'user' AND v1 = '/v1/user/' AND v2 = 'PUT') OR
+  (v0 = 'user' AND v1 = '/v1/user/:id' AND v2 = 'DELETE') OR
+  (v0 = 'admin' AND v1 = '/v1/user/:id/lockout' AND v2 = 'DELETE')
+);
+
+INSERT INTO casbin_rule (ptype, v0, v1, v2)
+SELECT 'p', 'user', '/v1/user/*', 'PUT|DELETE'
+WHERE EXISTS (SELECT 1 FROM casbin_rule)
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,12 @@
+-- users may only update and delete themselves, clearing a lockout is left to admins
+DELETE FROM casbin_rule WHERE ptype = 'p' AND v0 = 'user' AND v1 = '/v1/user/*' AND v2 = 'PUT|DELETE';
+
+INSERT INTO casbin_rule (ptype, v0, v1, v2)
+SELECT rule.ptype, rule.v0, rule.v1, rule.v2
+FROM (VALUES
+  ('p', 'user', '/v1/user/', 'PUT'),
+  ('p', 'user', '/v1/user/:id', 'DELETE'),
+  ('p', 'admin', '/v1/user/:id/lockout', 'DELETE')
+) AS rule (ptype, v0, v1, v2)
+WHERE EXISTS (SELECT 1 FROM casbin_rule)
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,9 @@
+DROP TRIGGER tweet_relation_count_status ON tweet;
+DROP TRIGGER tweet_relation_count ON tweet;
+
+DROP FUNCTION tweet_relation_count();
+
+ALTER TABLE tweet
+  DROP COLUMN quote_count,
+  DROP COLUMN retweet_count,
+  DROP COLUMN reply_count;
@@ -0,0 +1,41 @@
+ALTER TABLE tweet
+  ADD COLUMN reply_count int NOT NULL DEFAULT 0,
+  ADD COLUMN retweet_count int NOT NULL DEFAULT 0,
+  ADD COLUMN quote_count int NOT NULL DEFAULT 0;
+
+UPDATE tweet t SET
+  reply_count = (SELECT COUNT(1) FROM tweet reply WHERE reply.parent_id = t.id AND reply.status = 'published'),
+  retweet_count = (SELECT COUNT(1) FROM tweet rt WHERE rt.retweet_of_id = t.id AND rt.status = 'published'),
+  quote_count = (SELECT COUNT(1) FROM tweet quote WHERE quote.quote_of_id = t.id AND quote.status = 'published');
+
+-- published replies, retweets and quotes are counted on the tweet they point at,
+-- the counters follow inserts, deletes (cascades included) and publishing
+CREATE FUNCTION tweet_relation_count() RETURNS trigger AS $$
+BEGIN
+  IF TG_OP <> 'INSERT' THEN
+    IF OLD.status = 'published' THEN
+      UPDATE tweet SET reply_count = reply_count - 1 WHERE id = OLD.parent_id;
+      UPDATE tweet SET retweet_count = retweet_count - 1 WHERE id = OLD.retweet_of_id;
+      UPDATE tweet SET quote_count = quote_count - 1 WHERE id = OLD.quote_of_id;
+    END IF;
+  END IF;
+
+  IF TG_OP <> 'DELETE' THEN
+    IF NEW.status = 'published' THEN
+      UPDATE tweet SET reply_count = reply_count + 1 WHERE id = NEW.parent_id;
+      UPDATE tweet SET retweet_count = retweet_count + 1 WHERE id = NEW.retweet_of_id;
+      UPDATE tweet SET quote_count = quote_count + 1 WHERE id = NEW.quote_of_id;
+    END IF;
+  END IF;
+
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER tweet_relation_count
+AFTER INSERT OR DELETE ON tweet
+FOR EACH ROW EXECUTE FUNCTION tweet_relation_count();
+
+CREATE TRIGGER tweet_relation_count_status
+AFTER UPDATE OF status ON tweet
+FOR EACH ROW WHEN (OLD.status IS DISTINCT FROM NEW.status) EXECUTE FUNCTION tweet_relation_count();
@@ -5,6 +5,7 @@ import (
 	"net/smtp"
 	"strings"
 	"text/template"
+	"time"
 )
 
 type Otp struct {
@@ -36,15 +37,125 @@ func GenerateOtpEmailBody(otp string) (string, error) {
 	return builder.String(), nil
 }
 
+// GeneratePasswordResetEmailBody generates the HTML email body with a password reset otp
+func GeneratePasswordResetEmailBody(otp string) (string, error) {
+	templateString := `
+<!DOCTYPE html>
+<html>
+<body>
+    <p>Your Otp to reset your Mini twitter password {{.Code}},</p>
+    <p>If you did not request a password reset, please ignore this email.</p>
+</body>
+</html>
+`
+	tmpl, err := template.New("email").Parse(templateString)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse email template: %w", err)
+	}
+
+	var builder strings.Builder
+	err = tmpl.Execute(&builder, Otp{otp})
+	if err != nil {
+		return "", fmt.Errorf("failed to execute email template: %w", err)
+	}
+
+	return builder.String(), nil
+}
+
+// GenerateLockoutEmailBody generates the HTML email body sent when an account is locked
+func GenerateLockoutEmailBody(ipAddress string, lockedFor time.Duration) (string, error) {
+	templateString := `
+<!DOCTYPE html>
+<html>
+<body>
+    <p>Your Mini twitter account was temporarily locked for {{.Duration}} after too many failed login attempts.</p>
+    <p>The last attempt came from {{.IPAddress}}. If it was not you, please reset your password.</p>
+</body>
+</html>
+`
+	tmpl, err := template.New("email").Parse(templateString)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse email template: %w", err)
+	}
+
+	var builder strings.Builder
+	err = tmpl.Execute(&builder, struct {
+		IPAddress string
+		Duration  string
+	}{ipAddress, lockedFor.String()})
+	if err != nil {
+		return "", fmt.Errorf("failed to execute email template: %w", err)
+	}
+
+	return builder.String(), nil
+}
+
+// GenerateEmailChangeEmailBody generates the HTML email body with an otp to confirm a new email address
+func GenerateEmailChangeEmailBody(otp string) (string, error) {
+	templateString := `
+<!DOCTYPE html>
+<html>
+<body>
+    <p>Your Otp to confirm the new email address of your Mini twitter account {{.Code}},</p>
+    <p>If you did not request this change, please ignore this email.</p>
+</body>
+</html>
+`
+	tmpl, err := template.New("email").Parse(templateString)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse email template: %w", err)
+	}
+
+	var builder strings.Builder
+	err = tmpl.Execute(&builder, Otp{otp})
+	if err != nil {
+		return "", fmt.Errorf("failed to execute email template: %w", err)
+	}
+
+	return builder.String(), nil
+}
+
+// GenerateEmailChangedEmailBody generates the HTML email body sent to the old address after an email change
+func GenerateEmailChangedEmailBody(newEmail string) (string, error) {
+	templateString := `
+<!DOCTYPE html>
+<html>
+<body>
+    <p>The email address of your Mini twitter account has been changed to {{.Email}}.</p>
+    <p>If it was not you, please contact support immediately.</p>
+</body>
+</html>
+`
+	tmpl, err := template.New("email").Parse(templateString)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse email template: %w", err)
+	}
+
+	var builder strings.Builder
+	err = tmpl.Execute(&builder, struct {
+		Email string
+	}{newEmail})
+	if err != nil {
+		return "", fmt.Errorf("failed to execute email template: %w", err)
+	}
+
+	return builder.String(), nil
+}
+
 // sendEmail sends an email using SMTP
 func SendEmail(smtpHost, smtpPort, from, password, to, body string) error {
+	return SendEmailWithSubject(smtpHost, smtpPort, from, password, to, "Otp code Mini twitter", body)
+}
+
+// SendEmailWithSubject sends an email with the given subject using SMTP
+func SendEmailWithSubject(smtpHost, smtpPort, from, password, to, subject, body string) error {
 	auth := smtp.PlainAuth("", from, password, smtpHost)
 
-	msg := []byte(fmt.Sprintf("Subject: Otp code Mini twitter\r\n"+
+	msg := []byte(fmt.Sprintf("Subject: %s\r\n"+
 		"Content-Type: text/html; charset=\"UTF-8\"\r\n"+
 		"From: %s\r\n"+
 		"To: %s\r\n"+
-		"\r\n%s", from, to, body))
+		"\r\n%s", subject, from, to, body))
 
 	err := smtp.SendMail(smtpHost+":"+smtpPort, auth, from, []string{to}, msg)
 	if err != nil {
@@ -0,0 +1,23 @@
+package etc
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// RemoveFile removes a file stored under dir. Paths can't escape dir, remote urls and
+// files that don't exist are ignored.
+func RemoveFile(dir, path string) error {
+	if path == "" || strings.Contains(path, "://") {
+		return nil
+	}
+
+	err := os.Remove(filepath.Join(dir, filepath.Clean("/"+path)))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package etc
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+)
+
+// GenerateToken returns a URL-safe random token built from n random bytes.
+func GenerateToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// GenerateRecoveryCode returns a human friendly one-time code like "k7f2q-6xw4m".
+func GenerateRecoveryCode() (string, error) {
+	const charset = "abcdefghijklmnopqrstuvwxyz234567"
+
+	b := make([]byte, 10)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	for i := range b {
+		b[i] = charset[int(b[i])%len(charset)]
+	}
+
+	return string(b[:5]) + "-" + string(b[5:]), nil
+}
+
+// NormalizeRecoveryCode strips separators and case so user input can be compared with the stored hash.
+func NormalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
@@ -1,17 +1,48 @@
 package hash
 
 import (
+	"strings"
+
 	"golang.org/x/crypto/bcrypt"
 )
 
-// HashPassword hashes the given password using bcrypt.
-func HashPassword(password string) (string, error) {
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+// Bcrypt hashes passwords with bcrypt, it is kept to verify legacy hashes.
+type Bcrypt struct {
+	Cost int
+}
+
+// NewBcrypt -.
+func NewBcrypt(cost int) *Bcrypt {
+	if cost < bcrypt.MinCost {
+		cost = bcrypt.DefaultCost
+	}
+
+	return &Bcrypt{Cost: cost}
+}
+
+// Hash -.
+func (b *Bcrypt) Hash(password string) (string, error) {
+	bytes, err := bcrypt.GenerateFromPassword([]byte(password), b.Cost)
 	return string(bytes), err
 }
 
-// CheckPasswordHash compares a hashed password with its possible plaintext equivalent.
-func CheckPasswordHash(password, hash string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
-	return err == nil
+// Verify -.
+func (b *Bcrypt) Verify(password, encoded string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+	if err == bcrypt.ErrMismatchedHashAndPassword {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// Supports reports whether the hash was produced by bcrypt.
+func (b *Bcrypt) Supports(encoded string) bool {
+	return strings.HasPrefix(encoded, "$2a$") || strings.HasPrefix(encoded, "$2b$") || strings.HasPrefix(encoded, "$2y$")
+}
+
+// NeedsRehash -.
+func (b *Bcrypt) NeedsRehash(encoded string) bool {
+	cost, err := bcrypt.Cost([]byte(encoded))
+	return err != nil || cost != b.Cost
 }
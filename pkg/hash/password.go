@@ -0,0 +1,85 @@
+package hash
+
+import "sync"
+
+// Hasher is a password hashing algorithm. The encoded hash carries the algorithm and
+// its parameters, so hashes of every supported algorithm can live in the same column.
+type Hasher interface {
+	Hash(password string) (string, error)
+	Verify(password, encoded string) (bool, error)
+	// Supports reports whether the encoded hash was produced by this algorithm
+	Supports(encoded string) bool
+	// NeedsRehash reports whether the encoded hash uses outdated parameters
+	NeedsRehash(encoded string) bool
+}
+
+// PasswordHasher hashes new passwords with the preferred hasher and verifies
+// hashes of any of the known ones.
+type PasswordHasher struct {
+	preferred Hasher
+	hashers   []Hasher
+}
+
+// NewPasswordHasher -. Legacy hashers are used only to verify existing hashes.
+func NewPasswordHasher(preferred Hasher, legacy ...Hasher) *PasswordHasher {
+	return &PasswordHasher{
+		preferred: preferred,
+		hashers:   append([]Hasher{preferred}, legacy...),
+	}
+}
+
+// Hash -.
+func (p *PasswordHasher) Hash(password string) (string, error) {
+	return p.preferred.Hash(password)
+}
+
+// Verify compares the password with a hash of any known algorithm.
+func (p *PasswordHasher) Verify(password, encoded string) bool {
+	for _, h := range p.hashers {
+		if h.Supports(encoded) {
+			ok, err := h.Verify(password, encoded)
+			return err == nil && ok
+		}
+	}
+
+	return false
+}
+
+// NeedsRehash reports whether the hash should be replaced after a successful login,
+// because it was made with another algorithm or outdated parameters.
+func (p *PasswordHasher) NeedsRehash(encoded string) bool {
+	return !p.preferred.Supports(encoded) || p.preferred.NeedsRehash(encoded)
+}
+
+var (
+	mu            sync.RWMutex
+	defaultHasher = NewPasswordHasher(NewArgon2id(0, 0, 0), NewBcrypt(0))
+)
+
+// SetPasswordHasher replaces the hasher used by HashPassword, CheckPasswordHash and NeedsRehash.
+func SetPasswordHasher(p *PasswordHasher) {
+	mu.Lock()
+	defer mu.Unlock()
+	defaultHasher = p
+}
+
+func passwordHasher() *PasswordHasher {
+	mu.RLock()
+	defer mu.RUnlock()
+	return defaultHasher
+}
+
+// HashPassword hashes the given password with the preferred algorithm.
+func HashPassword(password string) (string, error) {
+	return passwordHasher().Hash(password)
+}
+
+// CheckPasswordHash compares a hashed password with its possible plaintext equivalent.
+func CheckPasswordHash(password, hash string) bool {
+	return passwordHasher().Verify(password, hash)
+}
+
+// NeedsRehash reports whether the stored hash should be replaced with a fresh one.
+func NeedsRehash(hash string) bool {
+	return passwordHasher().NeedsRehash(hash)
+}
@@ -0,0 +1,22 @@
+package hash
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// HashToken returns the hex encoded SHA-256 digest of the given token.
+// Use it for high-entropy secrets such as refresh tokens, not for passwords.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// HMAC returns the hex encoded HMAC-SHA256 of the value. Unlike HashToken it is safe
+// for low-entropy values such as otp codes, the digest can't be brute-forced without the key.
+func HMAC(value, key string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -6,6 +6,9 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// ErrTokenExpired is returned by ParseJWT when the token's exp claim is in the past.
+var ErrTokenExpired = jwt.ErrTokenExpired
+
 type JwtGenerateRequest struct {
 	Keys      map[string]interface{} `json:"keys"`
 	JwtKey    string
@@ -0,0 +1,430 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+
+	_defaultAlgorithm      = AlgorithmEdDSA
+	_defaultReloadInterval = time.Minute
+	_defaultRetention      = time.Hour
+	_defaultRSABits        = 2048
+	_minReloadGap          = 10 * time.Second
+)
+
+// Key is a signing key loaded from a PEM file, the file name without extension is its kid.
+type Key struct {
+	ID        string
+	Method    jwt.SigningMethod
+	Private   crypto.Signer
+	CreatedAt time.Time
+}
+
+// JWK -.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKS -.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// KeyRing signs tokens with the newest key of the directory and verifies them with
+// any key it holds, picked by the kid header. Without a directory it falls back to HS256
+// with the shared secret, the way GenerateJWT and ParseJWT work.
+type KeyRing struct {
+	dir              string
+	secret           string
+	algorithm        string
+	rotationInterval time.Duration
+	reloadInterval   time.Duration
+	retention        time.Duration
+
+	mu         sync.RWMutex
+	keys       map[string]*Key
+	active     *Key
+	lastReload time.Time
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewKeyRing loads the keys from dir and, if a rotation interval is set, starts rotating them.
+func NewKeyRing(dir string, opts ...Option) (*KeyRing, error) {
+	k := &KeyRing{
+		dir:            dir,
+		algorithm:      _defaultAlgorithm,
+		reloadInterval: _defaultReloadInterval,
+		retention:      _defaultRetention,
+		keys:           map[string]*Key{},
+		stop:           make(chan struct{}),
+	}
+
+	// Custom options
+	for _, opt := range opts {
+		opt(k)
+	}
+
+	if k.dir == "" {
+		if k.secret == "" {
+			return nil, errors.New("jwt - NewKeyRing - neither keys directory nor secret is set")
+		}
+
+		return k, nil
+	}
+
+	if err := os.MkdirAll(k.dir, 0o700); err != nil {
+		return nil, fmt.Errorf("jwt - NewKeyRing - os.MkdirAll: %w", err)
+	}
+
+	if err := k.maintain(); err != nil {
+		return nil, err
+	}
+
+	if k.active == nil {
+		return nil, fmt.Errorf("jwt - NewKeyRing - no keys found in %s", k.dir)
+	}
+
+	k.wg.Add(1)
+	go k.run()
+
+	return k, nil
+}
+
+// Close stops the background reload and rotation.
+func (k *KeyRing) Close() {
+	if k.dir == "" {
+		return
+	}
+
+	close(k.stop)
+	k.wg.Wait()
+}
+
+// Generate signs the claims with the active key.
+func (k *KeyRing) Generate(keys map[string]interface{}) (string, error) {
+	if k.dir == "" {
+		return GenerateJWT(keys, k.secret)
+	}
+
+	k.mu.RLock()
+	key := k.active
+	k.mu.RUnlock()
+
+	claims := jwt.MapClaims{}
+	for name, value := range keys {
+		claims[name] = value
+	}
+
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.ID
+
+	return token.SignedString(key.Private)
+}
+
+// Parse validates the token with the key its kid points to.
+func (k *KeyRing) Parse(tokenString string) (jwt.MapClaims, error) {
+	if k.dir == "" {
+		return ParseJWT(tokenString, k.secret)
+	}
+
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		if kid == "" {
+			return nil, errors.New("missing kid header")
+		}
+
+		key := k.key(kid)
+		if key == nil {
+			return nil, fmt.Errorf("unknown kid: %s", kid)
+		}
+
+		if token.Method.Alg() != key.Method.Alg() {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+
+		return key.Private.Public(), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return nil, fmt.Errorf("invalid token")
+	}
+
+	return claims, nil
+}
+
+// JWKS returns the public keys of the ring, empty in HS256 mode.
+func (k *KeyRing) JWKS() JWKS {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	set := JWKS{Keys: []JWK{}}
+
+	for _, key := range k.keys {
+		jwk := JWK{
+			Kid: key.ID,
+			Use: "sig",
+			Alg: key.Method.Alg(),
+		}
+
+		switch pub := key.Private.Public().(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		default:
+			continue
+		}
+
+		set.Keys = append(set.Keys, jwk)
+	}
+
+	return set
+}
+
+// Rotate generates a new key, writes it to the directory and makes it active.
+func (k *KeyRing) Rotate() error {
+	var (
+		private crypto.Signer
+		err     error
+	)
+
+	switch k.algorithm {
+	case AlgorithmRS256:
+		private, err = rsa.GenerateKey(rand.Reader, _defaultRSABits)
+	case AlgorithmEdDSA:
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		err = fmt.Errorf("unsupported algorithm: %s", k.algorithm)
+	}
+	if err != nil {
+		return fmt.Errorf("jwt - Rotate - generate key: %w", err)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return fmt.Errorf("jwt - Rotate - x509.MarshalPKCS8PrivateKey: %w", err)
+	}
+
+	suffix := make([]byte, 4)
+	if _, err = rand.Read(suffix); err != nil {
+		return err
+	}
+
+	kid := time.Now().UTC().Format("20060102T150405Z") + "-" + hex.EncodeToString(suffix)
+
+	// write to a temporary file first, so other instances never read a partial key
+	tmp := filepath.Join(k.dir, "."+kid+".tmp")
+	if err = os.WriteFile(tmp, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		return fmt.Errorf("jwt - Rotate - os.WriteFile: %w", err)
+	}
+
+	if err = os.Rename(tmp, filepath.Join(k.dir, kid+".pem")); err != nil {
+		return fmt.Errorf("jwt - Rotate - os.Rename: %w", err)
+	}
+
+	return k.Load()
+}
+
+// Load reads every *.pem file of the directory, the newest key becomes active.
+func (k *KeyRing) Load() error {
+	files, err := filepath.Glob(filepath.Join(k.dir, "*.pem"))
+	if err != nil {
+		return fmt.Errorf("jwt - Load - filepath.Glob: %w", err)
+	}
+
+	var (
+		keys   = make(map[string]*Key, len(files))
+		active *Key
+	)
+
+	for _, file := range files {
+		key, err := loadKey(file)
+		if err != nil {
+			return err
+		}
+
+		keys[key.ID] = key
+
+		if active == nil || key.CreatedAt.After(active.CreatedAt) {
+			active = key
+		}
+	}
+
+	k.mu.Lock()
+	k.keys = keys
+	k.active = active
+	k.lastReload = time.Now()
+	k.mu.Unlock()
+
+	return nil
+}
+
+func (k *KeyRing) key(kid string) *Key {
+	k.mu.RLock()
+	key, ok := k.keys[kid]
+	lastReload := k.lastReload
+	k.mu.RUnlock()
+
+	if ok {
+		return key
+	}
+
+	// the key may have just been created by another instance
+	if time.Since(lastReload) < _minReloadGap {
+		return nil
+	}
+
+	if err := k.Load(); err != nil {
+		return nil
+	}
+
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	return k.keys[kid]
+}
+
+func (k *KeyRing) run() {
+	defer k.wg.Done()
+
+	ticker := time.NewTicker(k.reloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-k.stop:
+			return
+		case <-ticker.C:
+			// errors are retried on the next tick, the ring keeps the keys it has
+			_ = k.maintain()
+		}
+	}
+}
+
+// maintain reloads the directory, rotates the active key when it is too old
+// and removes keys that can't have signed a still valid token.
+func (k *KeyRing) maintain() error {
+	if err := k.Load(); err != nil {
+		return err
+	}
+
+	if k.rotationInterval <= 0 {
+		return nil
+	}
+
+	k.mu.RLock()
+	active := k.active
+	keys := make([]*Key, 0, len(k.keys))
+	for _, key := range k.keys {
+		keys = append(keys, key)
+	}
+	k.mu.RUnlock()
+
+	if active == nil || time.Since(active.CreatedAt) >= k.rotationInterval {
+		if err := k.Rotate(); err != nil {
+			return err
+		}
+	}
+
+	pruned := false
+
+	for _, key := range keys {
+		if key != active && time.Since(key.CreatedAt) > k.rotationInterval+k.retention {
+			if err := os.Remove(filepath.Join(k.dir, key.ID+".pem")); err == nil {
+				pruned = true
+			}
+		}
+	}
+
+	if pruned {
+		return k.Load()
+	}
+
+	return nil
+}
+
+func loadKey(file string) (*Key, error) {
+	info, err := os.Stat(file)
+	if err != nil {
+		return nil, fmt.Errorf("jwt - loadKey - os.Stat: %w", err)
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("jwt - loadKey - os.ReadFile: %w", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("jwt - loadKey - %s is not a PEM file", file)
+	}
+
+	var parsed interface{}
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		err = fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("jwt - loadKey - %s: %w", file, err)
+	}
+
+	key := &Key{
+		ID:        strings.TrimSuffix(filepath.Base(file), filepath.Ext(file)),
+		CreatedAt: info.ModTime(),
+	}
+
+	switch private := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.Method = jwt.SigningMethodRS256
+		key.Private = private
+	case ed25519.PrivateKey:
+		key.Method = jwt.SigningMethodEdDSA
+		key.Private = private
+	default:
+		return nil, fmt.Errorf("jwt - loadKey - %s: unsupported key type %T", file, parsed)
+	}
+
+	return key, nil
+}
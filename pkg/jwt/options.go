@@ -0,0 +1,46 @@
+package jwt
+
+import "time"
+
+// Option -.
+type Option func(*KeyRing)
+
+// Secret is the HS256 secret used when the key ring has no keys directory.
+func Secret(secret string) Option {
+	return func(k *KeyRing) {
+		k.secret = secret
+	}
+}
+
+// Algorithm of the keys created on rotation, RS256 or EdDSA.
+func Algorithm(algorithm string) Option {
+	return func(k *KeyRing) {
+		if algorithm != "" {
+			k.algorithm = algorithm
+		}
+	}
+}
+
+// RotationInterval -. Zero disables rotation, keys are then managed outside the service.
+func RotationInterval(interval time.Duration) Option {
+	return func(k *KeyRing) {
+		k.rotationInterval = interval
+	}
+}
+
+// ReloadInterval -.
+func ReloadInterval(interval time.Duration) Option {
+	return func(k *KeyRing) {
+		if interval > 0 {
+			k.reloadInterval = interval
+		}
+	}
+}
+
+// Retention is how long a rotated key is kept for verification, it should be
+// longer than the lifetime of any token signed with it.
+func Retention(retention time.Duration) Option {
+	return func(k *KeyRing) {
+		k.retention = retention
+	}
+}
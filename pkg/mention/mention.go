@@ -0,0 +1,53 @@
+// Package mention finds @username mentions in tweet text.
+// A username is made of ascii letters, digits and underscores, an @ right after one of those or after another @
+// is not a mention, so e-mail addresses are skipped.
+package mention
+
+// MaxUsernameLength is the length of users.username, longer names are not mentions.
+const MaxUsernameLength = 50
+
+// Mention is an @username in a text. Start and End are offsets in unicode code points, the @ included
+// and End exclusive.
+type Mention struct {
+	Username string
+	Start    int
+	End      int
+}
+
+// Parse returns the mentions in the text in the order they appear.
+func Parse(text string) []Mention {
+	var (
+		response []Mention
+		runes    = []rune(text)
+	)
+
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != '@' || (i > 0 && (isUsernameRune(runes[i-1]) || runes[i-1] == '@')) {
+			continue
+		}
+
+		end := i + 1
+		for end < len(runes) && isUsernameRune(runes[end]) {
+			end++
+		}
+
+		length := end - i - 1
+
+		// @name@host is an address rather than a mention
+		if length > 0 && length <= MaxUsernameLength && (end == len(runes) || runes[end] != '@') {
+			response = append(response, Mention{
+				Username: string(runes[i+1 : end]),
+				Start:    i,
+				End:      end,
+			})
+		}
+
+		i = end - 1
+	}
+
+	return response
+}
+
+func isUsernameRune(r rune) bool {
+	return r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+}
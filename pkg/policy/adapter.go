@@ -0,0 +1,286 @@
+// Package policy stores casbin policies in postgres and keeps the enforcers of all
+// instances in sync through redis pub/sub.
+package policy
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/casbin/casbin/model"
+	"github.com/casbin/casbin/persist"
+	"github.com/golanguzb70/udevslabs-twitter/pkg/logger"
+	"github.com/golanguzb70/udevslabs-twitter/pkg/postgres"
+)
+
+const (
+	_table        = "casbin_rule"
+	_fieldsCount  = 6
+	_queryTimeout = 5 * time.Second
+)
+
+var _columns = []string{"v0", "v1", "v2", "v3", "v4", "v5"}
+
+// Adapter is a casbin persist.Adapter on top of the casbin_rule table.
+type Adapter struct {
+	pg       *postgres.Postgres
+	logger   *logger.Logger
+	seedFile string
+
+	// casbin clears the model before loading, the last loaded rules are kept so a failed
+	// reload doesn't leave the enforcer without any policy
+	mu   sync.Mutex
+	last [][]string
+}
+
+var _ persist.Adapter = (*Adapter)(nil)
+
+// NewAdapter -. If the table is empty on load, it is filled from the seedFile csv.
+func NewAdapter(pg *postgres.Postgres, logger *logger.Logger, seedFile string) *Adapter {
+	return &Adapter{
+		pg:       pg,
+		logger:   logger,
+		seedFile: seedFile,
+	}
+}
+
+// LoadPolicy loads all rules into the model.
+func (a *Adapter) LoadPolicy(m model.Model) error {
+	ctx, cancel := context.WithTimeout(context.Background(), _queryTimeout)
+	defer cancel()
+
+	rules, err := a.load(ctx)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if err != nil {
+		if a.last == nil {
+			return err
+		}
+
+		a.logger.Error(fmt.Errorf("policy - LoadPolicy - keeping the previous policy: %w", err))
+		rules = a.last
+	}
+
+	a.last = rules
+
+	for _, rule := range rules {
+		persist.LoadPolicyLine(strings.Join(rule, ", "), m)
+	}
+
+	return nil
+}
+
+// SavePolicy replaces all rules with the ones of the model.
+func (a *Adapter) SavePolicy(m model.Model) error {
+	ctx, cancel := context.WithTimeout(context.Background(), _queryTimeout)
+	defer cancel()
+
+	tx, err := a.pg.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, "DELETE FROM "+_table)
+	if err != nil {
+		return err
+	}
+
+	for _, sec := range []string{"p", "g"} {
+		for ptype, assertion := range m[sec] {
+			for _, rule := range assertion.Policy {
+				qeury, args, err := a.insertQuery(ptype, rule)
+				if err != nil {
+					return err
+				}
+
+				if _, err = tx.Exec(ctx, qeury, args...); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// AddPolicy -.
+func (a *Adapter) AddPolicy(_ string, ptype string, rule []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), _queryTimeout)
+	defer cancel()
+
+	qeury, args, err := a.insertQuery(ptype, rule)
+	if err != nil {
+		return err
+	}
+
+	_, err = a.pg.Pool.Exec(ctx, qeury, args...)
+
+	return err
+}
+
+// RemovePolicy -.
+func (a *Adapter) RemovePolicy(_ string, ptype string, rule []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), _queryTimeout)
+	defer cancel()
+
+	if len(rule) > _fieldsCount {
+		return fmt.Errorf("policy - RemovePolicy - rule has more than %d fields", _fieldsCount)
+	}
+
+	where := squirrel.Eq{"ptype": ptype}
+	for i, column := range _columns {
+		value := ""
+		if i < len(rule) {
+			value = rule[i]
+		}
+		where[column] = value
+	}
+
+	qeury, args, err := a.pg.Builder.Delete(_table).Where(where).ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = a.pg.Pool.Exec(ctx, qeury, args...)
+
+	return err
+}
+
+// RemoveFilteredPolicy removes the rules whose fields starting at fieldIndex match fieldValues, empty values match anything.
+func (a *Adapter) RemoveFilteredPolicy(_ string, ptype string, fieldIndex int, fieldValues ...string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), _queryTimeout)
+	defer cancel()
+
+	if fieldIndex < 0 || fieldIndex+len(fieldValues) > _fieldsCount {
+		return errors.New("policy - RemoveFilteredPolicy - invalid field index")
+	}
+
+	where := squirrel.Eq{"ptype": ptype}
+	for i, value := range fieldValues {
+		if value != "" {
+			where[_columns[fieldIndex+i]] = value
+		}
+	}
+
+	qeury, args, err := a.pg.Builder.Delete(_table).Where(where).ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = a.pg.Pool.Exec(ctx, qeury, args...)
+
+	return err
+}
+
+func (a *Adapter) load(ctx context.Context) ([][]string, error) {
+	rules, err := a.rules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rules) == 0 && a.seedFile != "" {
+		if err = a.seed(ctx); err != nil {
+			return nil, err
+		}
+
+		return a.rules(ctx)
+	}
+
+	return rules, nil
+}
+
+// rules returns every rule as [ptype, v0, ...] without trailing empty fields.
+func (a *Adapter) rules(ctx context.Context) ([][]string, error) {
+	qeury, args, err := a.pg.Builder.Select("ptype", "v0", "v1", "v2", "v3", "v4", "v5").
+		From(_table).OrderBy("id").ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := a.pg.Pool.Query(ctx, qeury, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules [][]string
+
+	for rows.Next() {
+		rule := make([]string, _fieldsCount+1)
+		if err = rows.Scan(&rule[0], &rule[1], &rule[2], &rule[3], &rule[4], &rule[5], &rule[6]); err != nil {
+			return nil, err
+		}
+
+		for len(rule) > 1 && rule[len(rule)-1] == "" {
+			rule = rule[:len(rule)-1]
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules, rows.Err()
+}
+
+// seed inserts the rules of the csv file. Instances starting at the same time may
+// both seed, the unique index keeps a single copy of every rule. Migrations adding rules
+// only insert them when the table isn't empty, a fresh table gets them from the csv here.
+func (a *Adapter) seed(ctx context.Context) error {
+	file, err := os.Open(a.seedFile)
+	if err != nil {
+		return fmt.Errorf("policy - seed - os.Open: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		tokens := strings.Split(line, ",")
+		for i := range tokens {
+			tokens[i] = strings.TrimSpace(tokens[i])
+		}
+
+		qeury, args, err := a.insertQuery(tokens[0], tokens[1:])
+		if err != nil {
+			return err
+		}
+
+		if _, err = a.pg.Pool.Exec(ctx, qeury, args...); err != nil {
+			return fmt.Errorf("policy - seed - insert: %w", err)
+		}
+	}
+
+	return scanner.Err()
+}
+
+func (a *Adapter) insertQuery(ptype string, rule []string) (string, []interface{}, error) {
+	if len(rule) > _fieldsCount {
+		return "", nil, fmt.Errorf("policy - rule has more than %d fields", _fieldsCount)
+	}
+
+	values := []interface{}{ptype}
+	for i := 0; i < _fieldsCount; i++ {
+		value := ""
+		if i < len(rule) {
+			value = rule[i]
+		}
+		values = append(values, value)
+	}
+
+	return a.pg.Builder.Insert(_table).
+		Columns("ptype", "v0", "v1", "v2", "v3", "v4", "v5").
+		Values(values...).
+		Suffix("ON CONFLICT DO NOTHING").ToSql()
+}
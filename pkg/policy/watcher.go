@@ -0,0 +1,84 @@
+package policy
+
+import (
+	"context"
+	"sync"
+
+	"github.com/casbin/casbin/persist"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// Watcher is a casbin persist.Watcher that notifies other instances through a redis channel.
+type Watcher struct {
+	client  *redis.Client
+	channel string
+	id      string
+
+	mu       sync.Mutex
+	callback func(string)
+
+	pubsub *redis.PubSub
+	done   chan struct{}
+}
+
+var _ persist.Watcher = (*Watcher)(nil)
+
+// NewWatcher subscribes to the channel, the subscription is ready when it returns.
+func NewWatcher(client *redis.Client, channel string) (*Watcher, error) {
+	w := &Watcher{
+		client:  client,
+		channel: channel,
+		id:      uuid.NewString(),
+		pubsub:  client.Subscribe(context.Background(), channel),
+		done:    make(chan struct{}),
+	}
+
+	if _, err := w.pubsub.Receive(context.Background()); err != nil {
+		w.pubsub.Close()
+		return nil, err
+	}
+
+	go w.listen()
+
+	return w, nil
+}
+
+// SetUpdateCallback -.
+func (w *Watcher) SetUpdateCallback(callback func(string)) error {
+	w.mu.Lock()
+	w.callback = callback
+	w.mu.Unlock()
+
+	return nil
+}
+
+// Update tells the other instances to reload the policy.
+func (w *Watcher) Update() error {
+	return w.client.Publish(context.Background(), w.channel, w.id).Err()
+}
+
+// Close -.
+func (w *Watcher) Close() {
+	w.pubsub.Close()
+	<-w.done
+}
+
+func (w *Watcher) listen() {
+	defer close(w.done)
+
+	for msg := range w.pubsub.Channel() {
+		// the instance that made the change already has it
+		if msg.Payload == w.id {
+			continue
+		}
+
+		w.mu.Lock()
+		callback := w.callback
+		w.mu.Unlock()
+
+		if callback != nil {
+			callback(msg.Payload)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+// Package ratelimit implements a sliding window rate limiter shared by all instances through redis.
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// slidingWindow keeps the request times of the window in a sorted set. Redis time is used,
+// so the clocks of the instances don't matter.
+var slidingWindow = redis.NewScript(`
+local key = KEYS[1]
+local window = tonumber(ARGV[1])
+local limit = tonumber(ARGV[2])
+
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+
+redis.call('ZREMRANGEBYSCORE', key, '-inf', now - window)
+
+local count = redis.call('ZCARD', key)
+local allowed = 0
+if count < limit then
+	redis.call('ZADD', key, now, ARGV[3])
+	count = count + 1
+	allowed = 1
+end
+redis.call('PEXPIRE', key, window)
+
+local reset = window
+local oldest = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+if oldest[2] then
+	reset = tonumber(oldest[2]) + window - now
+end
+
+return {allowed, count, reset}
+`)
+
+// Result -.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is the time until the oldest request of the window expires
+	Reset time.Duration
+}
+
+// Limiter -.
+type Limiter struct {
+	client *redis.Client
+	prefix string
+}
+
+// New -.
+func New(client *redis.Client, prefix string) *Limiter {
+	return &Limiter{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+// Allow records a request for the key and reports whether it fits into limit requests per window.
+func (l *Limiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (Result, error) {
+	res, err := slidingWindow.Run(ctx, l.client, []string{l.prefix + key}, window.Milliseconds(), limit, uuid.NewString()).Int64Slice()
+	if err != nil {
+		return Result{}, err
+	}
+
+	return Result{
+		Allowed:   res[0] == 1,
+		Limit:     limit,
+		Remaining: limit - int(res[1]),
+		Reset:     time.Duration(res[2]) * time.Millisecond,
+	}, nil
+}
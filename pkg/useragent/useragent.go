@@ -0,0 +1,109 @@
+// Package useragent extracts a human readable device description from a User-Agent header.
+// It only knows the common browsers, operating systems and http clients, anything else is "Other".
+package useragent
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Device -.
+type Device struct {
+	Browser string
+	OS      string
+	App     string
+	Mobile  bool
+}
+
+type rule struct {
+	name    string
+	pattern *regexp.Regexp
+}
+
+// order matters, e.g. Edge and Opera also send "Chrome" and Chrome also sends "Safari"
+var browsers = []rule{
+	{"Edge", regexp.MustCompile(`Edg(?:e|A|iOS)?/(\d+)`)},
+	{"Opera", regexp.MustCompile(`(?:OPR|Opera)/(\d+)`)},
+	{"Samsung Internet", regexp.MustCompile(`SamsungBrowser/(\d+)`)},
+	{"Yandex", regexp.MustCompile(`YaBrowser/(\d+)`)},
+	{"Firefox", regexp.MustCompile(`(?:Firefox|FxiOS)/(\d+)`)},
+	{"Chrome", regexp.MustCompile(`(?:Chrome|CriOS)/(\d+)`)},
+	{"Safari", regexp.MustCompile(`Version/(\d+)[\d.]* (?:Mobile/\S+ )?Safari/`)},
+}
+
+var systems = []rule{
+	{"iOS", regexp.MustCompile(`(?:iPhone|iPad|iPod).*? OS (\d+)`)},
+	{"Android", regexp.MustCompile(`Android (\d+)`)},
+	{"Windows", regexp.MustCompile(`Windows NT (\d+)`)},
+	{"ChromeOS", regexp.MustCompile(`CrOS \S+ (\d+)`)},
+	{"macOS", regexp.MustCompile(`Mac OS X`)},
+	{"Linux", regexp.MustCompile(`Linux`)},
+}
+
+// http clients and libraries used by scripts and our mobile apps
+var apps = []rule{
+	{"Mini twitter", regexp.MustCompile(`MiniTwitter/(\d+)`)},
+	{"curl", regexp.MustCompile(`^curl/(\d+)`)},
+	{"Postman", regexp.MustCompile(`^PostmanRuntime/(\d+)`)},
+	{"Python", regexp.MustCompile(`^python-(?:requests|httpx)/(\d+)`)},
+	{"Go", regexp.MustCompile(`^Go-http-client/(\d+)`)},
+	{"OkHttp", regexp.MustCompile(`^okhttp/(\d+)`)},
+	{"Dart", regexp.MustCompile(`^Dart/(\d+)`)},
+}
+
+// Parse -.
+func Parse(ua string) Device {
+	device := Device{
+		Browser: "Other",
+		OS:      "Other",
+		Mobile:  strings.Contains(ua, "Mobile") || strings.Contains(ua, "Android"),
+	}
+
+	if ua == "" {
+		return device
+	}
+
+	if name, ok := match(apps, ua); ok {
+		device.App = name
+	}
+
+	if name, ok := match(browsers, ua); ok {
+		device.Browser = name
+	}
+
+	if name, ok := match(systems, ua); ok {
+		device.OS = name
+	}
+
+	return device
+}
+
+// String returns a short description like "Chrome 120 on Windows 10".
+func (d Device) String() string {
+	if d.App != "" && d.Browser == "Other" {
+		if d.OS == "Other" {
+			return d.App
+		}
+
+		return d.App + " on " + d.OS
+	}
+
+	return d.Browser + " on " + d.OS
+}
+
+func match(rules []rule, ua string) (string, bool) {
+	for _, r := range rules {
+		m := r.pattern.FindStringSubmatch(ua)
+		if m == nil {
+			continue
+		}
+
+		if len(m) > 1 && m[1] != "" {
+			return r.name + " " + m[1], true
+		}
+
+		return r.name, true
+	}
+
+	return "", false
+}